@@ -0,0 +1,91 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// AddOptionAlias registers additional names for the option with the given
+// short or long name. Aliases of a single character are short options, longer
+// aliases are long options. Only the primary names are shown in the usage
+// output, with the aliases listed after the description. Returns
+// ErrOptionNotFound if no such option has been added, ErrInvalidOption if an
+// alias is not a valid option name and ErrDuplicateOption if an alias is
+// already in use.
+func (s *Usage) AddOptionAlias(name string, aliases ...string) error {
+	return s.addAliases(name, aliases, false)
+}
+
+// AddRenamedAlias registers former names of the option with the given short
+// or long name, e.g. after renaming --out to --output:
+//
+//	u.AddRenamedAlias("output", "out")
+//
+// Former names keep setting the option but each use prints a deprecation
+// warning naming the replacement. They are not shown in the usage output.
+func (s *Usage) AddRenamedAlias(name string, oldNames ...string) error {
+	return s.addAliases(name, oldNames, true)
+}
+
+// addAliases registers names as aliases, or former names when renamed is
+// true, of the option registered under name.
+func (s *Usage) addAliases(name string, names []string, renamed bool) error {
+	name = strings.TrimLeft(name, "-")
+	option := s.LookupOption(name)
+	f := s.flagSet.Lookup(name)
+	if option == nil || f == nil {
+		return fmt.Errorf("%w: %s", ErrOptionNotFound, name)
+	}
+	seen := make(map[string]bool)
+	for _, alias := range names {
+		if err := s.checkName(alias); err != nil {
+			return err
+		}
+		if seen[alias] {
+			return fmt.Errorf("%w: name %q given twice", ErrInvalidOption, alias)
+		}
+		seen[alias] = true
+	}
+	for _, alias := range names {
+		s.flagSet.Var(f.Value, alias, f.Usage)
+		if renamed {
+			option.RenamedFrom = append(option.RenamedFrom, alias)
+		} else {
+			option.Aliases = append(option.Aliases, alias)
+		}
+	}
+	return nil
+}
+
+// checkName returns ErrInvalidOption for a name that cannot be registered,
+// see validName, and ErrDuplicateOption if name is already in use by an
+// option or flag.
+func (s *Usage) checkName(name string) error {
+	if !validName(name) {
+		return fmt.Errorf("%w: invalid name %q", ErrInvalidOption, name)
+	}
+	if s.flagSet.Lookup(name) != nil || s.LookupOption(name) != nil {
+		return fmt.Errorf("%w: %s", ErrDuplicateOption, name)
+	}
+	return nil
+}
+
+// environmentValue returns the value of the environment variable of option,
+// or of the first of its former environment variables that is set, in which
+// case a deprecation warning naming the current variable is printed. The name
+// of the variable is returned along with the value.
+func (s *Usage) environmentValue(option *internal.Option) (string, string, bool) {
+	if value, ok := os.LookupEnv(option.Env); ok && option.Env != "" {
+		return option.Env, value, true
+	}
+	for _, name := range option.RenamedEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			s.printWarning(fmt.Sprintf("environment variable %s is deprecated, use %s instead", name, option.Env))
+			return name, value, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,161 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// addAliasOptions adds the output option the alias tests use to sage.
+func addAliasOptions(t *testing.T, sage *usage.Usage) *string {
+	t.Helper()
+	output, err := sage.AddStringOptionE("o", "output", "", "Output file", "", nil)
+	assert.NoError(t, err)
+	return output
+}
+
+func TestAddOptionAlias(t *testing.T) {
+	var errBuf bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &errBuf, nil)),
+	)
+	output := addAliasOptions(t, sage)
+	assert.NoError(t, sage.AddOptionAlias("output", "O", "out-file"))
+
+	for _, args := range [][]string{{"-O", "a.txt"}, {"--out-file=a.txt"}} {
+		*output = ""
+		assert.NoError(t, sage.ParseArgs(args))
+		assert.Equal(t, "a.txt", *output)
+	}
+	assert.Empty(t, errBuf.String())
+	assert.True(t, sage.IsSet("output"))
+	assert.Equal(t, sage.LookupOption("output"), sage.LookupOption("--out-file"))
+	assert.Equal(t, "Output file [aliases: -O, --out-file]", sage.LookupOption("output").HelpDescription())
+}
+
+func TestAddRenamedAlias(t *testing.T) {
+	var errBuf bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &errBuf, nil)),
+	)
+	output := addAliasOptions(t, sage)
+	assert.NoError(t, sage.AddRenamedAlias("output", "dest"))
+
+	assert.NoError(t, sage.ParseArgs([]string{"--dest", "b.txt"}))
+	assert.Equal(t, "b.txt", *output)
+	assert.Equal(t, "Warning: option --dest is deprecated, use --output instead\n", errBuf.String())
+	assert.Equal(t, "Output file", sage.LookupOption("output").HelpDescription())
+}
+
+func TestAddOptionAliasErrors(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	addAliasOptions(t, sage)
+	_, err := sage.AddBooleanOptionE("v", "verbose", false, "Verbose output", "", nil)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, sage.AddOptionAlias("missing", "m"), usage.ErrOptionNotFound)
+	assert.ErrorIs(t, sage.AddOptionAlias("output", "verbose"), usage.ErrDuplicateOption)
+	assert.ErrorIs(t, sage.AddRenamedAlias("output", "v"), usage.ErrDuplicateOption)
+	assert.ErrorIs(t, sage.AddOptionAlias("output", ""), usage.ErrInvalidOption)
+	assert.ErrorIs(t, sage.AddOptionAlias("output", "o=x"), usage.ErrInvalidOption)
+	assert.ErrorIs(t, sage.AddRenamedAlias("output", "-out"), usage.ErrInvalidOption)
+	assert.ErrorIs(t, sage.AddOptionAlias("output", "out", "out"), usage.ErrInvalidOption)
+	assert.Empty(t, sage.LookupOption("output").Aliases)
+
+	_, err = sage.String("level").Alias("l=x").Build()
+	assert.ErrorIs(t, err, usage.ErrInvalidOption)
+	_, err = sage.String("mode").RenamedFrom("-m").Build()
+	assert.ErrorIs(t, err, usage.ErrInvalidOption)
+	var config struct {
+		Format string `usage:"aliases=f|f"`
+		Color  string `usage:"renamed=-colour"`
+	}
+	assert.ErrorIs(t, sage.Bind(&config), usage.ErrInvalidOption)
+}
+
+func TestBuilderAliases(t *testing.T) {
+	var errBuf bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &errBuf, nil)),
+	)
+	addAliasOptions(t, sage)
+	level, err := sage.String("log-level").Alias("L").RenamedFrom("verbosity").Env("APP_LOG_LEVEL", "APP_VERBOSITY").Build()
+	assert.NoError(t, err)
+
+	assert.NoError(t, sage.ParseArgs([]string{"-L", "debug"}))
+	assert.Equal(t, "debug", *level)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--verbosity", "info"}))
+	assert.Equal(t, "info", *level)
+	assert.Contains(t, errBuf.String(), "option --verbosity is deprecated, use --log-level instead")
+}
+
+func TestRenamedEnvironmentVariable(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		want        string
+		wantWarning bool
+	}{
+		{"former variable", map[string]string{"APP_VERBOSITY": "warn"}, "warn", true},
+		{"current variable wins", map[string]string{"APP_VERBOSITY": "warn", "APP_LOG_LEVEL": "error"}, "error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errBuf bytes.Buffer
+			sage := usage.NewUsage(
+				usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+				usage.WithoutVersionFlag(),
+				usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &errBuf, nil)),
+			)
+			addAliasOptions(t, sage)
+			level, err := sage.String("log-level").Env("APP_LOG_LEVEL", "APP_VERBOSITY").Build()
+			assert.NoError(t, err)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			assert.NoError(t, sage.ParseArgs(nil))
+			assert.Equal(t, tt.want, *level)
+			if tt.wantWarning {
+				assert.Contains(t, errBuf.String(), "environment variable APP_VERBOSITY is deprecated, use APP_LOG_LEVEL instead")
+			} else {
+				assert.Empty(t, errBuf.String())
+			}
+		})
+	}
+}
+
+func TestBindAliases(t *testing.T) {
+	var errBuf bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &errBuf, nil)),
+	)
+	var config struct {
+		Output string `usage:"aliases=O|out-file,renamed=dest"`
+	}
+	assert.NoError(t, sage.Bind(&config))
+
+	assert.NoError(t, sage.ParseArgs([]string{"--out-file", "a.txt"}))
+	assert.Equal(t, "a.txt", config.Output)
+	assert.NoError(t, sage.ParseArgs([]string{"--dest", "b.txt"}))
+	assert.Equal(t, "b.txt", config.Output)
+	assert.Contains(t, errBuf.String(), "option --dest is deprecated, use --output instead")
+}
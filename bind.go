@@ -0,0 +1,203 @@
+package usage
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// Bind registers an option for every exported field of the struct pointed to
+// by target. The settings of each option are read from the `usage` struct tag,
+// a comma separated list of key=value pairs and flags:
+//
+//	type Config struct {
+//	    Verbose bool          `usage:"short=v,desc=Enable verbose output"`
+//	    Timeout time.Duration `usage:"short=t,long=timeout,desc=Request timeout,env=TIMEOUT,required"`
+//	    Hosts   []string      `usage:"desc='Hosts to query, in order'"`
+//	    Debug   bool          `usage:"-"`
+//	    Proxy   struct {
+//	        URL string `usage:"long=proxy-url,desc=Proxy to use"`
+//	    } `usage:"group=Proxy,desc=Proxy settings"`
+//	}
+//
+// The supported keys are short, long, desc, extra, group, env, default,
+// choices (separated by "|") and deprecated, and the supported flags are
+// required, secret, hidden and advanced. When
+// neither short nor long is given the long name is derived from the field
+// name, e.g. MaxRetries becomes --max-retries. The current value of a field is
+// its default unless a default key is given. Values containing commas can be
+// enclosed in single quotes and the tag "-" skips a field.
+//
+// Nested structs become option groups named after the group key or the field
+// name, while embedded structs add their fields to the enclosing group.
+// Fields may be bools, integers, floats, strings, time.Duration, slices and
+// maps of those, or any type implementing flag.Value or
+// encoding.TextUnmarshaler.
+//
+// Options bound this way appear in the usage output and are parsed and
+// validated exactly like options added with the Add*Option methods. Bind
+// returns an error wrapping ErrInvalidBinding for unsupported fields or tags
+// and ErrDuplicateOption for names that are already in use.
+func (s *Usage) Bind(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: target must be a non-nil pointer to a struct, got %T", ErrInvalidBinding, target)
+	}
+	return s.bindStruct(v.Elem(), nil)
+}
+
+// bindStruct binds the exported fields of the struct v to options in group.
+func (s *Usage) bindStruct(v reflect.Value, group *internal.Group) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, err := internal.ParseFieldTag(field.Tag.Get("usage"))
+		if err != nil {
+			return fmt.Errorf("%w: field %s: %v", ErrInvalidBinding, field.Name, err)
+		}
+		if tag.Skip {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && !internal.IsValueType(fv.Type()) {
+			nested := group
+			if !field.Anonymous || tag.Group != "" {
+				name := tag.Group
+				if name == "" {
+					name = field.Name
+				}
+				nested = s.bindGroup(name, tag)
+			}
+			if err := s.bindStruct(fv, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.bindField(field, fv, tag, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindGroup returns the group with the given name, creating it after the
+// existing groups if it doesn't exist, and applies the group settings of tag.
+func (s *Usage) bindGroup(name string, tag *internal.FieldTag) *internal.Group {
+	group, ok := s.configuration.Groups[name]
+	if !ok {
+		group = s.AddGroup(len(s.configuration.Groups), name, tag.Description)
+	}
+	if group.Description == "" {
+		group.Description = tag.Description
+	}
+	group.Hidden = group.Hidden || tag.Hidden
+	group.Advanced = group.Advanced || tag.Advanced
+	if tag.Deprecated != "" {
+		group.Deprecated = tag.Deprecated
+	}
+	return group
+}
+
+// bindField registers the option for a single struct field.
+func (s *Usage) bindField(field reflect.StructField, fv reflect.Value, tag *internal.FieldTag, group *internal.Group) error {
+	value, err := internal.NewValue(fv)
+	if err != nil {
+		return fmt.Errorf("%w: field %s: %v", ErrInvalidBinding, field.Name, err)
+	}
+	if tag.HasDefault {
+		if err := value.Set(tag.Default); err != nil {
+			return fmt.Errorf("%w: field %s: invalid default %q: %v", ErrInvalidBinding, field.Name, tag.Default, err)
+		}
+		// Wrap the field again so the default counts as unset, and the first
+		// value given replaces a default slice or map instead of adding to it
+		value, _ = internal.NewValue(fv)
+	}
+
+	short, long := tag.Short, tag.Long
+	if short == "" && long == "" {
+		long = internal.KebabCase(field.Name)
+	}
+	if tag.Group != "" {
+		group = s.bindGroup(tag.Group, &internal.FieldTag{})
+	}
+
+	return s.registerOption(&internal.Option{
+		Short:       short,
+		Long:        long,
+		Default:     defaultOf(fv, value),
+		Description: tag.Description,
+		Extra:       tag.Extra,
+		Hidden:      tag.Hidden,
+		Advanced:    tag.Advanced,
+		Deprecated:  tag.Deprecated,
+		Env:         tag.Env,
+		Required:    tag.Required,
+		Choices:     tag.Choices,
+		Secret:      tag.Secret,
+		Aliases:     tag.Aliases,
+		RenamedFrom: tag.RenamedFrom,
+		RenamedEnv:  tag.RenamedEnv,
+		Type:        internal.ValueTypeName(fv.Type()),
+	}, value, group)
+}
+
+// registerOption adds option to group and registers value with the flag set
+// under the names of the option, including its aliases and former names. It
+// returns ErrInvalidOption if one of the names cannot be used, see validName,
+// or is given twice, and ErrDuplicateOption if one of the names is already in
+// use.
+func (s *Usage) registerOption(option *internal.Option, value flag.Value, group *internal.Group) error {
+	seen := make(map[string]bool)
+	for _, name := range option.Names() {
+		if !validName(name) {
+			return fmt.Errorf("%w: invalid name %q", ErrInvalidOption, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: name %q given twice", ErrInvalidOption, name)
+		}
+		seen[name] = true
+		if s.flagSet.Lookup(name) != nil || s.LookupOption(name) != nil {
+			return fmt.Errorf("%w: %s", ErrDuplicateOption, name)
+		}
+	}
+	if err := s.addOptionToGroup(option, group); err != nil {
+		return err
+	}
+	for _, name := range option.Names() {
+		s.flagSet.Var(value, name, option.Description)
+	}
+	return nil
+}
+
+// validName reports whether name can be registered with the flag set, which
+// rejects empty names, names starting with "-" and names containing "=".
+func validName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "-") && !strings.Contains(name, "=")
+}
+
+// defaultOf returns the default value shown for a bound field. Basic values are
+// kept as bools, ints, floats and strings so formatters can render them as
+// such, any other value is shown in its command line form.
+func defaultOf(fv reflect.Value, value fmt.Stringer) interface{} {
+	if internal.IsValueType(fv.Type()) {
+		return value.String()
+	}
+	switch fv.Kind() {
+	case reflect.Bool:
+		return fv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return int(fv.Int())
+	case reflect.Float32, reflect.Float64:
+		return fv.Float()
+	case reflect.String:
+		return fv.String()
+	}
+	return value.String()
+}
@@ -0,0 +1,162 @@
+package usage_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindConfig struct {
+	Verbose bool          `usage:"short=v,desc=Enable verbose output"`
+	Timeout time.Duration `usage:"short=t,long=timeout,desc=Request timeout,group=Request,env=BIND_TEST_TIMEOUT"`
+	Retries int           `usage:"default=3,desc=Number of retries,group=Request"`
+	Hosts   []string      `usage:"long=host,desc='Hosts to query, in order'"`
+	Labels  map[string]string
+	Token   string `usage:"required,desc=API token"`
+	Ignored string `usage:"-"`
+	Proxy   struct {
+		URL     string `usage:"long=proxy-url,desc=Proxy to use"`
+		Enabled bool   `usage:"long=proxy"`
+	} `usage:"desc=Proxy settings"`
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	cfg := bindConfig{Timeout: 10 * time.Second}
+	sage := usage.NewUsage(
+		usage.WithApplicationName("bind"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	assert.NoError(t, sage.Bind(&cfg))
+	assert.Equal(t, 3, cfg.Retries)
+
+	err := sage.ParseArgs([]string{
+		"-v", "-t", "1m", "--retries", "5",
+		"--host", "a,b", "--host", "c",
+		"--labels", "env=prod",
+		"--token", "secret",
+		"--proxy-url", "http://proxy", "--proxy",
+	})
+	assert.NoError(t, err)
+	assert.True(t, cfg.Verbose)
+	assert.Equal(t, time.Minute, cfg.Timeout)
+	assert.Equal(t, 5, cfg.Retries)
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Hosts)
+	assert.Equal(t, map[string]string{"env": "prod"}, cfg.Labels)
+	assert.Equal(t, "secret", cfg.Token)
+	assert.Equal(t, "http://proxy", cfg.Proxy.URL)
+	assert.True(t, cfg.Proxy.Enabled)
+}
+
+func TestBindDefaultReplaced(t *testing.T) {
+	cfg := struct {
+		Tags   []string          `usage:"long=tag,default=a"`
+		Labels map[string]string `usage:"default=env=dev"`
+	}{}
+	sage := usage.NewUsage(
+		usage.WithApplicationName("bind"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	assert.NoError(t, sage.Bind(&cfg))
+	assert.Equal(t, []string{"a"}, cfg.Tags)
+	assert.Equal(t, map[string]string{"env": "dev"}, cfg.Labels)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--tag", "z", "--labels", "tier=web"}))
+	assert.Equal(t, []string{"z"}, cfg.Tags)
+	assert.Equal(t, map[string]string{"tier": "web"}, cfg.Labels)
+}
+
+func TestBindEnvironment(t *testing.T) {
+	t.Setenv("BIND_TEST_TIMEOUT", "45s")
+
+	cfg := bindConfig{}
+	sage := usage.NewUsage(
+		usage.WithApplicationName("bind"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	assert.NoError(t, sage.Bind(&cfg))
+	assert.NoError(t, sage.ParseArgs([]string{"--token", "x"}))
+	assert.Equal(t, 45*time.Second, cfg.Timeout)
+
+	// The command line takes precedence over the environment
+	cfg = bindConfig{}
+	sage = usage.NewUsage(
+		usage.WithApplicationName("bind"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	assert.NoError(t, sage.Bind(&cfg))
+	assert.NoError(t, sage.ParseArgs([]string{"--token", "x", "--timeout", "5s"}))
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+}
+
+func TestBindEnvironmentInvalid(t *testing.T) {
+	t.Setenv("BIND_TEST_TIMEOUT", "soon")
+
+	sage := usage.NewUsage(
+		usage.WithApplicationName("bind"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	assert.NoError(t, sage.Bind(&bindConfig{}))
+	err := sage.ParseArgs([]string{"--token", "x"})
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindInvalidValue, usageErr.Kind)
+		assert.Equal(t, "timeout", usageErr.Option)
+		assert.Equal(t, "environment variable BIND_TEST_TIMEOUT", usageErr.Source)
+	}
+}
+
+func TestBindRequired(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("bind"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	assert.NoError(t, sage.Bind(&bindConfig{}))
+	err := sage.ParseArgs([]string{"-v"})
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindMissingOption, usageErr.Kind)
+		assert.Equal(t, "token", usageErr.Option)
+		assert.Equal(t, "missing required option --token", usageErr.Error())
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  interface{}
+		wantErr error
+	}{
+		{"not a pointer", bindConfig{}, usage.ErrInvalidBinding},
+		{"not a struct", new(string), usage.ErrInvalidBinding},
+		{"unsupported type", &struct{ C chan int }{}, usage.ErrInvalidBinding},
+		{"invalid tag", &struct {
+			A string `usage:"shrt=a"`
+		}{}, usage.ErrInvalidBinding},
+		{"invalid default", &struct {
+			A int `usage:"default=abc"`
+		}{}, usage.ErrInvalidBinding},
+		{"duplicate name", &struct {
+			A string `usage:"long=name"`
+			B string `usage:"long=name"`
+		}{}, usage.ErrDuplicateOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+			err := sage.Bind(tt.target)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
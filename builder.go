@@ -0,0 +1,212 @@
+package usage
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// OptionBuilder declares an option step by step, as an alternative to the
+// positional parameters of the Add*Option methods. Builders are created with
+// String, Bool, Int, Float, Duration, Strings or NewOption and the option is
+// registered by Var or Build:
+//
+//	var output string
+//	u.String("output").Short("o").Help("Output filename").Env("OUT").Required().In(group).Var(&output)
+//
+// Builders never panic. Errors such as a duplicate name or an unknown group
+// are returned by Build, or by ParseArgs for options registered with Var.
+type OptionBuilder[T any] struct {
+	usage      *Usage
+	option     internal.Option
+	group      *internal.Group
+	value      T
+	hasDefault bool
+}
+
+// NewOption returns a builder for an option with the given long name and a
+// value of any type supported by Bind, e.g. a slice, a map or a type
+// implementing flag.Value.
+func NewOption[T any](u *Usage, name string) *OptionBuilder[T] {
+	return &OptionBuilder[T]{usage: u, option: internal.Option{Long: name}}
+}
+
+// String returns a builder for a string option with the given long name.
+func (s *Usage) String(name string) *OptionBuilder[string] {
+	return NewOption[string](s, name)
+}
+
+// Bool returns a builder for a boolean option with the given long name.
+func (s *Usage) Bool(name string) *OptionBuilder[bool] {
+	return NewOption[bool](s, name)
+}
+
+// Int returns a builder for an integer option with the given long name.
+func (s *Usage) Int(name string) *OptionBuilder[int] {
+	return NewOption[int](s, name)
+}
+
+// Float returns a builder for a float64 option with the given long name.
+func (s *Usage) Float(name string) *OptionBuilder[float64] {
+	return NewOption[float64](s, name)
+}
+
+// Duration returns a builder for a time.Duration option with the given long
+// name. Values are parsed with time.ParseDuration, e.g. "30s" or "1m30s".
+func (s *Usage) Duration(name string) *OptionBuilder[time.Duration] {
+	return NewOption[time.Duration](s, name)
+}
+
+// Strings returns a builder for a string slice option with the given long
+// name. The option may be repeated and accepts comma separated values.
+func (s *Usage) Strings(name string) *OptionBuilder[[]string] {
+	return NewOption[[]string](s, name)
+}
+
+// Short sets the single-character name of the option.
+func (b *OptionBuilder[T]) Short(name string) *OptionBuilder[T] {
+	b.option.Short = name
+	return b
+}
+
+// Default sets the value used when the option is not provided. Without a
+// default the option starts with the current value of the variable passed to
+// Var, or the zero value when using Build.
+func (b *OptionBuilder[T]) Default(value T) *OptionBuilder[T] {
+	b.value = value
+	b.hasDefault = true
+	return b
+}
+
+// Help sets the description shown in the usage output.
+func (b *OptionBuilder[T]) Help(description string) *OptionBuilder[T] {
+	b.option.Description = description
+	return b
+}
+
+// Extra sets the additional information shown in the usage output.
+func (b *OptionBuilder[T]) Extra(extra string) *OptionBuilder[T] {
+	b.option.Extra = extra
+	return b
+}
+
+// Env sets the environment variable that provides the value when the option
+// is not given on the command line. Former names of the variable can follow,
+// they are still read when the variable is not set but print a deprecation
+// warning naming the current variable.
+func (b *OptionBuilder[T]) Env(name string, renamedFrom ...string) *OptionBuilder[T] {
+	b.option.Env = name
+	b.option.RenamedEnv = renamedFrom
+	return b
+}
+
+// Alias adds names under which the option is also accepted, see
+// AddOptionAlias.
+func (b *OptionBuilder[T]) Alias(names ...string) *OptionBuilder[T] {
+	b.option.Aliases = append(b.option.Aliases, names...)
+	return b
+}
+
+// RenamedFrom adds former names of the option that still work but print a
+// deprecation warning, see AddRenamedAlias.
+func (b *OptionBuilder[T]) RenamedFrom(names ...string) *OptionBuilder[T] {
+	b.option.RenamedFrom = append(b.option.RenamedFrom, names...)
+	return b
+}
+
+// Required marks the option as required. Parsing fails when it is given
+// neither on the command line nor through its environment variable.
+func (b *OptionBuilder[T]) Required() *OptionBuilder[T] {
+	b.option.Required = true
+	return b
+}
+
+// Choices restricts the values of the option to the given values. The
+// choices are listed in the usage output and shown as a menu when prompting.
+func (b *OptionBuilder[T]) Choices(values ...string) *OptionBuilder[T] {
+	b.option.Choices = values
+	return b
+}
+
+// Secret marks the value of the option as sensitive, see SetOptionSecret.
+func (b *OptionBuilder[T]) Secret() *OptionBuilder[T] {
+	b.option.Secret = true
+	return b
+}
+
+// Hidden hides the option from the usage output, see HideOption.
+func (b *OptionBuilder[T]) Hidden() *OptionBuilder[T] {
+	b.option.Hidden = true
+	return b
+}
+
+// Advanced only shows the option with --help-all, see SetOptionAdvanced.
+func (b *OptionBuilder[T]) Advanced() *OptionBuilder[T] {
+	b.option.Advanced = true
+	return b
+}
+
+// Deprecated marks the option as deprecated with a message and the name of
+// the replacing option, either of which may be empty, see DeprecateOption.
+func (b *OptionBuilder[T]) Deprecated(message string, replacement string) *OptionBuilder[T] {
+	if message == "" {
+		message = "it will be removed in a future release"
+	}
+	b.option.Deprecated = message
+	b.option.Replacement = replacement
+	return b
+}
+
+// In places the option in the given group instead of GROUP_DEFAULT.
+func (b *OptionBuilder[T]) In(group *internal.Group) *OptionBuilder[T] {
+	b.group = group
+	return b
+}
+
+// Var registers the option and stores its value in the variable p points to.
+// Any error is reported when the arguments are parsed.
+func (b *OptionBuilder[T]) Var(p *T) {
+	if err := b.register(p); err != nil {
+		b.usage.declarationErrors = append(b.usage.declarationErrors, err)
+	}
+}
+
+// Build registers the option and returns a pointer to its value, or an error
+// if the option cannot be registered.
+func (b *OptionBuilder[T]) Build() (*T, error) {
+	p := new(T)
+	if err := b.register(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// register validates the declaration and registers the option with p as its
+// value.
+func (b *OptionBuilder[T]) register(p *T) error {
+	name := b.option.Name()
+	if name == "" {
+		return fmt.Errorf("%w: an option needs a short or long name", ErrInvalidOption)
+	}
+	if p == nil {
+		return fmt.Errorf("%w: %s: nil variable", ErrInvalidOption, name)
+	}
+	if len([]rune(b.option.Short)) > 1 {
+		return fmt.Errorf("%w: %s: short name %q must be a single character", ErrInvalidOption, name, b.option.Short)
+	}
+	if b.hasDefault {
+		*p = b.value
+	}
+
+	v := reflect.ValueOf(p).Elem()
+	value, err := internal.NewValue(v)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidOption, name, err)
+	}
+	option := b.option
+	option.Default = defaultOf(v, value)
+	option.Type = internal.ValueTypeName(v.Type())
+	return b.usage.registerOption(&option, value, b.group)
+}
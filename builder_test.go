@@ -0,0 +1,175 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/internal"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionBuilder(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("builder"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	group := sage.AddGroup(1, "Request", "Request options")
+
+	var output string
+	sage.String("output").Short("o").Default("out.txt").Help("Output filename").Var(&output)
+	verbose, err := sage.Bool("verbose").Short("v").Build()
+	assert.NoError(t, err)
+	retries, err := sage.Int("retries").Default(3).In(group).Build()
+	assert.NoError(t, err)
+	ratio, err := sage.Float("ratio").Default(1.5).Build()
+	assert.NoError(t, err)
+	timeout, err := sage.Duration("timeout").Default(10 * time.Second).In(group).Build()
+	assert.NoError(t, err)
+	hosts, err := sage.Strings("host").Build()
+	assert.NoError(t, err)
+	labels, err := usage.NewOption[map[string]int](sage, "weight").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "out.txt", output)
+
+	err = sage.ParseArgs([]string{"-o", "result.txt", "-v", "--retries", "5", "--timeout", "1m", "--host", "a,b", "--weight", "x=1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "result.txt", output)
+	assert.True(t, *verbose)
+	assert.Equal(t, 5, *retries)
+	assert.Equal(t, 1.5, *ratio)
+	assert.Equal(t, time.Minute, *timeout)
+	assert.Equal(t, []string{"a", "b"}, *hosts)
+	assert.Equal(t, map[string]int{"x": 1}, *labels)
+}
+
+func TestOptionBuilderEnvAndRequired(t *testing.T) {
+	t.Setenv("BUILDER_TEST_OUT", "env.txt")
+
+	sage := usage.NewUsage(
+		usage.WithApplicationName("builder"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	var output, token string
+	sage.String("output").Env("BUILDER_TEST_OUT").Var(&output)
+	sage.String("token").Required().Var(&token)
+
+	err := sage.ParseArgs(nil)
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindMissingOption, usageErr.Kind)
+		assert.Equal(t, "token", usageErr.Option)
+	}
+
+	assert.NoError(t, sage.ParseArgs([]string{"--token", "x"}))
+	assert.Equal(t, "env.txt", output)
+	assert.Equal(t, "x", token)
+}
+
+func TestOptionBuilderDeprecated(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithFormatter(pkg.NewStandardFormatter(&outBuf, &errBuf, nil)),
+	)
+	var output string
+	sage.String("output").Var(&output)
+	sage.String("out").Deprecated("renamed", "output").Hidden().Advanced().Var(&output)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--out", "a.txt"}))
+	assert.Equal(t, "a.txt", output)
+	assert.Contains(t, errBuf.String(), "option --out is deprecated: renamed, use --output instead")
+}
+
+func TestOptionBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		declare func(u *usage.Usage)
+		wantErr error
+	}{
+		{
+			name:    "no name",
+			declare: func(u *usage.Usage) { u.String("").Var(new(string)) },
+			wantErr: usage.ErrInvalidOption,
+		},
+		{
+			name:    "long short name",
+			declare: func(u *usage.Usage) { u.String("output").Short("out").Var(new(string)) },
+			wantErr: usage.ErrInvalidOption,
+		},
+		{
+			name:    "short name equal to long name",
+			declare: func(u *usage.Usage) { u.Bool("x").Short("x").Var(new(bool)) },
+			wantErr: usage.ErrInvalidOption,
+		},
+		{
+			name:    "leading dash",
+			declare: func(u *usage.Usage) { u.String("--output").Var(new(string)) },
+			wantErr: usage.ErrInvalidOption,
+		},
+		{
+			name:    "equals sign",
+			declare: func(u *usage.Usage) { u.String("a=b").Var(new(string)) },
+			wantErr: usage.ErrInvalidOption,
+		},
+		{
+			name:    "nil variable",
+			declare: func(u *usage.Usage) { u.Int("count").Var(nil) },
+			wantErr: usage.ErrInvalidOption,
+		},
+		{
+			name:    "unsupported type",
+			declare: func(u *usage.Usage) { usage.NewOption[chan int](u, "events").Var(new(chan int)) },
+			wantErr: usage.ErrInvalidOption,
+		},
+		{
+			name: "duplicate name",
+			declare: func(u *usage.Usage) {
+				u.String("output").Var(new(string))
+				u.Bool("output").Var(new(bool))
+			},
+			wantErr: usage.ErrDuplicateOption,
+		},
+		{
+			name: "unknown group",
+			declare: func(u *usage.Usage) {
+				u.String("output").In(pkg.NewGroup(1, "Missing", "")).Var(new(string))
+			},
+			wantErr: usage.ErrGroupNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sage := usage.NewUsage(
+				usage.WithApplicationName("builder"),
+				usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+				usage.WithoutVersionFlag(),
+			)
+			assert.NotPanics(t, func() { tt.declare(sage) })
+			assert.ErrorIs(t, sage.ParseArgs(nil), tt.wantErr)
+		})
+	}
+}
+
+func TestOptionBuilderBuildError(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("builder"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	_, err := sage.String("output").Build()
+	assert.NoError(t, err)
+
+	value, err := sage.String("output").Build()
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, usage.ErrDuplicateOption)
+
+	// Errors returned by Build are not reported again by ParseArgs
+	assert.NoError(t, sage.ParseArgs(nil))
+}
@@ -22,6 +22,7 @@ func main() {
 		usage.WithApplicationBuildDate(buildDate),
 		usage.WithApplicationCommitHash(commitHash),
 		usage.WithApplicationBranch(branch),
+		usage.WithBuildInfo(),
 		usage.WithApplicationDescription("It's almost a browser but not quite. Instead it's just a example of how to use the 'usage' package. It is designed to show an example of how to use the package and a sample of what the output would look like."))
 
 	// Add some options using the new error-returning methods
@@ -56,6 +57,11 @@ func main() {
 
 	url := sage.AddArgument(1, "url", "The url of the page to retrieve", "Extra")
 
+	// Add some examples and a footer to the help output
+	sage.AddExample("bowser https://example.com", "Retrieve a page using the default settings")
+	sage.AddExample("bowser -r HEAD -t 5 https://example.com", "Send a HEAD request with a 5 second timeout")
+	sage.AddSection("Bugs", "Report issues at https://github.com/bgrewell/usage/issues", usage.SECTION_BOTTOM)
+
 	parsed := sage.Parse()
 
 	if !parsed {
@@ -0,0 +1,145 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+const (
+	// ALIAS_SECTION is the title of the help section listing command aliases.
+	ALIAS_SECTION = "Aliases"
+
+	// ALIAS_CONFIG_SECTION is the section of configuration files that
+	// defines command aliases.
+	ALIAS_CONFIG_SECTION = "alias"
+
+	// ALIAS_DEPTH_LIMIT is the maximum number of nested aliases an alias may
+	// expand to before the expansion is considered runaway.
+	ALIAS_DEPTH_LIMIT = 10
+)
+
+// CommandAlias is a user-defined shortcut for a list of arguments, see
+// AddCommandAlias.
+type CommandAlias = internal.CommandAlias
+
+// AddCommandAlias defines an alias that stands for the arguments in
+// expansion, which are split with shell-like quoting:
+//
+//	u.AddCommandAlias("co", "checkout --force")
+//	u.AddCommandAlias("wip", `commit -m "work in progress"`)
+//
+// When the first positional argument of the command line is the name of an
+// alias it is replaced by the expansion before anything is parsed, so
+// "app -v co main" is parsed as "app -v checkout --force main". An expansion
+// may start with another alias, up to ALIAS_DEPTH_LIMIT levels deep. Aliases
+// are listed in the "Aliases" section of the help. Aliases defined in the
+// [alias] section of the configuration files, see WithConfigFiles, override
+// those defined with AddCommandAlias.
+//
+// Returns ErrInvalidAlias if the name is empty, contains whitespace, starts
+// with "-" or "@" so that it would shadow options or response files, or is
+// the name of a plugin command, or if the expansion is empty or has
+// unbalanced quotes. Returns ErrDuplicateAlias if an alias with the name has
+// already been added.
+func (s *Usage) AddCommandAlias(name string, expansion string) error {
+	if s.aliases[name] != nil {
+		return fmt.Errorf("%w: %s", ErrDuplicateAlias, name)
+	}
+	if err := s.checkAliasName(name); err != nil {
+		return err
+	}
+	alias, err := internal.NewCommandAlias(name, expansion, "")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAlias, err)
+	}
+	s.defineAlias(alias)
+	return nil
+}
+
+// CommandAliases returns the defined command aliases ordered by name.
+func (s *Usage) CommandAliases() []*CommandAlias {
+	aliases := make([]*CommandAlias, 0, len(s.aliases))
+	for _, alias := range s.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Name < aliases[j].Name
+	})
+	return aliases
+}
+
+// checkAliasName returns an error if name cannot be used for an alias.
+func (s *Usage) checkAliasName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n") || name[0] == '-' || name[0] == '@' {
+		return fmt.Errorf("%w: %q", ErrInvalidAlias, name)
+	}
+	s.loadPlugins()
+	for _, plugin := range s.plugins {
+		if plugin.Name == name {
+			return fmt.Errorf("%w: %s shadows a plugin command", ErrInvalidAlias, name)
+		}
+	}
+	return nil
+}
+
+// defineAlias adds or replaces alias and updates the help section listing
+// the aliases.
+func (s *Usage) defineAlias(alias *CommandAlias) {
+	if s.aliases == nil {
+		s.aliases = make(map[string]*CommandAlias)
+	}
+	s.aliases[alias.Name] = alias
+
+	aliases := s.CommandAliases()
+	width := 0
+	for _, alias := range aliases {
+		if len(alias.Name) > width {
+			width = len(alias.Name)
+		}
+	}
+	lines := make([]string, len(aliases))
+	for i, alias := range aliases {
+		lines[i] = fmt.Sprintf("%-*s  %s", width, alias.Name, alias.Expansion)
+	}
+	if s.aliasSection == nil {
+		s.aliasSection = &internal.Section{Title: ALIAS_SECTION, Position: SECTION_BOTTOM}
+		s.configuration.Sections = append(s.configuration.Sections, s.aliasSection)
+	}
+	s.aliasSection.Body = strings.Join(lines, "\n")
+}
+
+// applyConfigAliases defines the aliases of the [alias] sections of the
+// configuration files. Aliases with names that cannot be used are skipped
+// with a warning, an expansion with unbalanced quotes is an error.
+func (s *Usage) applyConfigAliases() error {
+	for _, entry := range s.configSection(ALIAS_CONFIG_SECTION) {
+		if err := s.checkAliasName(entry.Key); err != nil {
+			s.printWarning(fmt.Sprintf("%s: alias ignored: %v", entry.Source, err))
+			continue
+		}
+		alias, err := internal.NewCommandAlias(entry.Key, entry.Value, entry.Source)
+		if err != nil {
+			return configError(err.Error(), err)
+		}
+		s.defineAlias(alias)
+	}
+	return nil
+}
+
+// expandAliases replaces the alias named by the first positional argument of
+// tokens by its expansion.
+func (s *Usage) expandAliases(tokens []internal.Token) ([]internal.Token, error) {
+	if len(s.aliases) == 0 {
+		return tokens, nil
+	}
+	expander := &internal.AliasExpander{
+		Configuration: s.configuration,
+		Normalizer:    s.argNormalizer(),
+		Aliases:       s.aliases,
+		Limit:         ALIAS_DEPTH_LIMIT,
+	}
+	return expander.ExpandTokens(tokens)
+}
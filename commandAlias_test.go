@@ -0,0 +1,181 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// addCommandAliasOptions adds the force option and the command argument the
+// command alias tests use to sage.
+func addCommandAliasOptions(t *testing.T, sage *usage.Usage) (*bool, *string) {
+	t.Helper()
+	force, err := sage.AddBooleanOptionE("f", "force", false, "Force", "", nil)
+	assert.NoError(t, err)
+	command := sage.AddArgument(1, "command", "Command", "")
+	return force, command
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.ini")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestAddCommandAlias(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	force, command := addCommandAliasOptions(t, sage)
+	assert.NoError(t, sage.AddCommandAlias("co", "--force checkout"))
+	assert.NoError(t, sage.AddCommandAlias("c", "co"))
+
+	assert.NoError(t, sage.ParseArgs([]string{"c", "main"}))
+	assert.True(t, *force)
+	assert.Equal(t, "checkout main", *command)
+
+	aliases := sage.CommandAliases()
+	assert.Len(t, aliases, 2)
+	assert.Equal(t, "c", aliases[0].Name)
+	sections := sage.Model().SectionsAt(usage.SECTION_BOTTOM)
+	assert.Len(t, sections, 1)
+	assert.Equal(t, usage.ALIAS_SECTION, sections[0].Title)
+	assert.Equal(t, "c   co\nco  --force checkout", sections[0].Body)
+}
+
+func TestCommandAliasAfterAbbreviation(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithAbbreviations(),
+		usage.WithNegatedBooleans(),
+	)
+	force, command := addCommandAliasOptions(t, sage)
+	output := sage.AddStringOption("", "output", "", "Output file", "", nil)
+	assert.NoError(t, sage.AddCommandAlias("co", "checkout"))
+
+	assert.NoError(t, sage.ParseArgs([]string{"--out", "f", "co"}))
+	assert.Equal(t, "f", *output)
+	assert.Equal(t, "checkout", *command)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--no-for", "co"}))
+	assert.False(t, *force)
+	assert.Equal(t, "checkout", *command)
+}
+
+func TestAddCommandAliasErrors(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	addCommandAliasOptions(t, sage)
+	assert.NoError(t, sage.AddCommandAlias("co", "checkout"))
+
+	assert.ErrorIs(t, sage.AddCommandAlias("co", "commit"), usage.ErrDuplicateAlias)
+	for _, name := range []string{"", "-f", "@file", "two words"} {
+		assert.ErrorIs(t, sage.AddCommandAlias(name, "checkout"), usage.ErrInvalidAlias, name)
+	}
+	assert.ErrorIs(t, sage.AddCommandAlias("empty", " "), usage.ErrInvalidAlias)
+	assert.ErrorIs(t, sage.AddCommandAlias("quote", `commit -m "wip`), usage.ErrInvalidAlias)
+}
+
+func TestCommandAliasLoop(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	addCommandAliasOptions(t, sage)
+	assert.NoError(t, sage.AddCommandAlias("a", "b"))
+	assert.NoError(t, sage.AddCommandAlias("b", "--force a"))
+
+	err := sage.ParseArgs([]string{"a"})
+	var usageErr *usage.UsageError
+	assert.ErrorAs(t, err, &usageErr)
+	assert.Equal(t, "alias", string(usageErr.Kind))
+	assert.Equal(t, "alias loop: a -> b -> a", err.Error())
+}
+
+func TestCommandAliasInResponseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "args.rsp")
+	assert.NoError(t, os.WriteFile(path, []byte("--force\nco main\n"), 0o644))
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithResponseFiles(),
+	)
+	force, command := addCommandAliasOptions(t, sage)
+	assert.NoError(t, sage.AddCommandAlias("co", "checkout"))
+	assert.NoError(t, sage.AddCommandAlias("bad", "--bogus checkout"))
+
+	assert.NoError(t, sage.ParseArgs([]string{"@" + path}))
+	assert.True(t, *force)
+	assert.Equal(t, "checkout main", *command)
+
+	// The arguments of an alias are reported at the place the alias was used
+	assert.NoError(t, os.WriteFile(path, []byte("bad\n"), 0o644))
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, sage.ParseArgs([]string{"@" + path}), &usageErr) {
+		assert.Equal(t, "bogus", usageErr.Option)
+		assert.Equal(t, path+":1", usageErr.Source)
+	}
+}
+
+func TestConfigFileAliases(t *testing.T) {
+	path := writeConfigFile(t, "[alias]\nco = --force checkout\nwip = commit -m 'work in progress'\n-x = ignored\n")
+	var errBuf bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &errBuf, nil)),
+		usage.WithConfigFiles(filepath.Join(t.TempDir(), "missing.ini"), path),
+	)
+	force, command := addCommandAliasOptions(t, sage)
+	assert.NoError(t, sage.AddCommandAlias("co", "checkout"))
+
+	assert.NoError(t, sage.ParseArgs([]string{"co"}))
+	assert.True(t, *force)
+	assert.Equal(t, "checkout", *command)
+	assert.Equal(t, "Warning: "+path+":4: alias ignored: invalid alias: \"-x\"\n", errBuf.String())
+	assert.Equal(t, path+":3", sage.CommandAliases()[1].Source)
+}
+
+func TestConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"syntax", "[alias]\nco\n", ":2: expected key = value"},
+		{"quoting", "[alias]\nwip = commit -m \"wip\n", ":2: unterminated double quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.content)
+			sage := usage.NewUsage(
+				usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+				usage.WithoutVersionFlag(),
+				usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+				usage.WithConfigFiles(path),
+			)
+			addCommandAliasOptions(t, sage)
+			err := sage.ParseArgs(nil)
+			var usageErr *usage.UsageError
+			assert.ErrorAs(t, err, &usageErr)
+			assert.Equal(t, "config_file", string(usageErr.Kind))
+			assert.Equal(t, path+tt.want, err.Error())
+		})
+	}
+}
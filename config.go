@@ -0,0 +1,164 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// WithConfigFiles reads settings from the INI files at paths, in that order,
+// when the command line is parsed. Files that do not exist are skipped, so
+// paths can include optional user and system configuration files. Settings
+// of later files override those of earlier files. Settings before the first
+// section set the options that are not given on the command line or through
+// the environment, by any of their names. The [alias] section defines command
+// aliases, see AddCommandAlias, and [profile "name"] sections define profiles,
+// see WithProfiles:
+//
+//	timeout = 30s
+//	tag = web
+//	tag = api
+//
+//	[alias]
+//	co = checkout --force
+func WithConfigFiles(paths ...string) UsageOption {
+	return func(u *Usage) {
+		u.configFiles = append(u.configFiles, paths...)
+	}
+}
+
+// loadConfig reads the configuration files once and applies their aliases
+// and profile listing.
+// A file that cannot be read or parsed results in a *UsageError, which is
+// returned on every call.
+func (s *Usage) loadConfig() error {
+	if s.configLoaded {
+		return s.configErr
+	}
+	s.configLoaded = true
+	for _, path := range s.configFiles {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			s.configErr = configError(fmt.Sprintf("cannot read config file: %v", err), err)
+			return s.configErr
+		}
+		entries, err := internal.ParseIni(string(data), path)
+		if err != nil {
+			s.configErr = configError(err.Error(), err)
+			return s.configErr
+		}
+		s.configLayers = append(s.configLayers, entries)
+	}
+	s.configErr = s.applyConfigAliases()
+	s.updateProfileSection()
+	return s.configErr
+}
+
+// configError returns a UsageError for a problem with a configuration file.
+func configError(message string, err error) *internal.UsageError {
+	return &internal.UsageError{
+		Kind:    internal.ErrorKindConfigFile,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// configSection returns the entries of the configuration files in the
+// section with the given name, in the order they were read.
+func (s *Usage) configSection(section string) []internal.IniEntry {
+	var entries []internal.IniEntry
+	for _, layer := range s.configLayers {
+		entries = append(entries, sectionEntries(layer, section, "")...)
+	}
+	return entries
+}
+
+// sectionEntries returns the entries of layer in the given section and
+// subsection.
+func sectionEntries(layer []internal.IniEntry, section string, subsection string) []internal.IniEntry {
+	var entries []internal.IniEntry
+	for _, entry := range layer {
+		if entry.Section == section && entry.Subsection == subsection {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// applyConfigValues sets the options that have not been set from the
+// settings before the first section of the configuration files, where later
+// files override earlier ones.
+func (s *Usage) applyConfigValues() error {
+	layers := make([][]internal.IniEntry, len(s.configLayers))
+	for i, layer := range s.configLayers {
+		layers[i] = sectionEntries(layer, "", "")
+	}
+	return s.applyLayers(layers)
+}
+
+// applyLayers sets the options that have not been set from the settings in
+// layers. An option set in several layers takes the values of the last of
+// them, all of its settings in that layer are applied so that repeated
+// settings add to options that accept several values.
+func (s *Usage) applyLayers(layers [][]internal.IniEntry) error {
+	last := make(map[*internal.Option]int)
+	for i, layer := range layers {
+		for _, entry := range layer {
+			option, err := s.configOption(entry)
+			if err != nil {
+				return err
+			}
+			last[option] = i
+		}
+	}
+
+	set := s.setOptions()
+	for i, layer := range layers {
+		for _, entry := range layer {
+			option, _ := s.configuration.FindOption(entry.Key)
+			if set[option] || last[option] != i {
+				continue
+			}
+			if err := s.setValue(option, option.Name(), unquote(entry.Value)); err != nil {
+				err.Source = entry.Source
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// configOption returns the option a setting of a configuration file refers
+// to by any of its names, warning about deprecated and former names, or a
+// *UsageError if there is no such option.
+func (s *Usage) configOption(entry internal.IniEntry) (*internal.Option, error) {
+	option, _ := s.configuration.FindOption(entry.Key)
+	if option == nil {
+		err := &internal.UsageError{
+			Kind:    internal.ErrorKindUnknownOption,
+			Option:  entry.Key,
+			Message: fmt.Sprintf("unknown option %s", entry.Key),
+			Source:  entry.Source,
+			Err:     &internal.UnknownOptionError{Location: internal.NoLocation, Name: entry.Key, Err: ErrOptionNotFound},
+		}
+		err.SuggestOptions(s.configuration, s.suggestionDistance)
+		return nil, err
+	}
+	if warning, ok := s.configuration.DeprecationWarning(entry.Key); ok {
+		s.printWarning(entry.Source + ": " + warning)
+	}
+	return option, nil
+}
+
+// unquote removes the double or single quotes around a value of a
+// configuration file, e.g. name = "Jane Doe".
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
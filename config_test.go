@@ -0,0 +1,56 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFileValues(t *testing.T) {
+	system := writeConfigFile(t, "region = us-east-1\nname = 'system'\nretries = 1\n")
+	user := writeConfigFile(t, "# user settings\nname = \"Jane Doe\"\n")
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithConfigFiles(system, user),
+	)
+	region, _ := sage.AddStringOptionE("", "region", "", "Region", "", nil)
+	name, _ := sage.AddStringOptionE("", "name", "", "Name", "", nil)
+	retries, _ := sage.AddIntegerOptionE("", "retries", 3, "Retries", "", nil)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--retries", "5"}))
+	assert.Equal(t, "us-east-1", *region)
+	assert.Equal(t, "Jane Doe", *name)
+	assert.Equal(t, 5, *retries)
+	assert.True(t, sage.IsSet("region"))
+}
+
+func TestConfigFileValueErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown option", "regin = us-east-1\n", ":1: unknown option regin (did you mean --region?)"},
+		{"invalid value", "\nretries = many\n", `:2: invalid value "many" for flag --retries: parse error`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.content)
+			sage := usage.NewUsage(
+				usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+				usage.WithoutVersionFlag(),
+				usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+				usage.WithConfigFiles(path),
+			)
+			_, _ = sage.AddStringOptionE("", "region", "", "Region", "", nil)
+			_, _ = sage.AddIntegerOptionE("", "retries", 3, "Retries", "", nil)
+			assert.EqualError(t, sage.ParseArgs(nil), path+tt.want)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package usage
+
+import "github.com/bgrewell/usage/internal"
+
+// The errors returned by ParseArgs are *UsageErrors, or an ErrorList of them
+// when several problems are found together. The underlying error of a
+// UsageError is one of the typed errors below, which carry the definition of
+// the offending option or argument and the Location of the offending token:
+//
+//	var invalid *usage.InvalidValueError
+//	if errors.As(err, &invalid) {
+//	    fmt.Println(invalid.Option.Long, invalid.Value, invalid.Index)
+//	}
+
+// Location identifies the command-line token an error refers to by its Index
+// in the parsed arguments. The Index is -1 for errors that do not refer to a
+// token and the number of arguments for errors at the end of the line.
+type Location = internal.Location
+
+// UnknownOptionError reports an option that is not registered.
+type UnknownOptionError = internal.UnknownOptionError
+
+// AmbiguousOptionError reports an abbreviated option that matches several
+// options, see WithAbbreviations.
+type AmbiguousOptionError = internal.AmbiguousOptionError
+
+// InvalidValueError reports a value that cannot be converted to the type of
+// its option.
+type InvalidValueError = internal.InvalidValueError
+
+// MissingValueError reports an option that requires a value but was given
+// without one.
+type MissingValueError = internal.MissingValueError
+
+// MissingOptionError reports a required option that was not provided.
+type MissingOptionError = internal.MissingOptionError
+
+// MissingArgumentError reports a required positional argument that was not
+// provided.
+type MissingArgumentError = internal.MissingArgumentError
+
+// ConstraintError reports a value that violates a constraint of its option,
+// such as not being one of its choices.
+type ConstraintError = internal.ConstraintError
+
+// ErrorList aggregates several errors found together. errors.Is and errors.As
+// match any of its errors.
+type ErrorList = internal.ErrorList
@@ -0,0 +1,137 @@
+package usage_test
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/stretchr/testify/assert"
+)
+
+// addErrorsOptions adds the options the error tests use to sage.
+func addErrorsOptions(t *testing.T, sage *usage.Usage) {
+	t.Helper()
+	_, err := sage.AddIntegerOptionE("p", "port", 80, "Port", "", nil)
+	assert.NoError(t, err)
+	_, err = sage.AddBooleanOptionE("v", "verbose", false, "Verbose output", "", nil)
+	assert.NoError(t, err)
+	_, err = sage.String("color").Default("auto").Choices("auto", "never").Build()
+	assert.NoError(t, err)
+}
+
+func TestParseErrorTypes(t *testing.T) {
+	t.Run("unknown option", func(t *testing.T) {
+		sage := usage.NewUsage(
+			usage.WithApplicationName("app"),
+			usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+			usage.WithoutVersionFlag(),
+		)
+		addErrorsOptions(t, sage)
+		err := sage.ParseArgs([]string{"-v", "--prot", "1"})
+		var target *usage.UnknownOptionError
+		if assert.ErrorAs(t, err, &target) {
+			assert.Equal(t, "prot", target.Name)
+			assert.Equal(t, "--prot", target.Token)
+			assert.Equal(t, 1, target.Index)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		sage := usage.NewUsage(
+			usage.WithApplicationName("app"),
+			usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+			usage.WithoutVersionFlag(),
+		)
+		addErrorsOptions(t, sage)
+		err := sage.ParseArgs([]string{"-v", "--port", "abc"})
+		var target *usage.InvalidValueError
+		if assert.ErrorAs(t, err, &target) {
+			assert.Equal(t, "port", target.Option.Long)
+			assert.Equal(t, "abc", target.Value)
+			assert.Equal(t, 2, target.Index)
+		}
+		var usageErr *usage.UsageError
+		if assert.ErrorAs(t, err, &usageErr) {
+			assert.Equal(t, []string{"app -v --port abc", "              ^^^"}, usageErr.Highlight("app"))
+		}
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		sage := usage.NewUsage(
+			usage.WithApplicationName("app"),
+			usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+			usage.WithoutVersionFlag(),
+		)
+		addErrorsOptions(t, sage)
+		err := sage.ParseArgs([]string{"-v", "-p"})
+		var target *usage.MissingValueError
+		if assert.ErrorAs(t, err, &target) {
+			assert.Equal(t, "port", target.Option.Long)
+			assert.Equal(t, 1, target.Index)
+		}
+	})
+
+	t.Run("constraint", func(t *testing.T) {
+		sage := usage.NewUsage(
+			usage.WithApplicationName("app"),
+			usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+			usage.WithoutVersionFlag(),
+		)
+		addErrorsOptions(t, sage)
+		err := sage.ParseArgs([]string{"--color", "red", "-v"})
+		var target *usage.ConstraintError
+		if assert.ErrorAs(t, err, &target) {
+			assert.Equal(t, "red", target.Value)
+			assert.Equal(t, 1, target.Index)
+			assert.Equal(t, "must be one of auto, never", target.Constraint)
+		}
+	})
+}
+
+func TestParseErrorList(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addErrorsOptions(t, sage)
+	_, err := sage.String("name").Required().Build()
+	assert.NoError(t, err)
+	_, err = sage.String("region").Required().Build()
+	assert.NoError(t, err)
+	sage.AddArgument(1, "host", "Target host", "")
+	assert.NoError(t, sage.RequireArgument("host"))
+
+	err = sage.ParseArgs([]string{"--color=red"})
+	var list usage.ErrorList
+	if assert.ErrorAs(t, err, &list) {
+		assert.Len(t, list, 4)
+	}
+
+	var constraint *usage.ConstraintError
+	assert.ErrorAs(t, err, &constraint)
+	var missingOption *usage.MissingOptionError
+	if assert.ErrorAs(t, err, &missingOption) {
+		assert.Equal(t, "name", missingOption.Option.Long)
+	}
+	var missingArgument *usage.MissingArgumentError
+	if assert.ErrorAs(t, err, &missingArgument) {
+		assert.Equal(t, "host", missingArgument.Argument.Name)
+		assert.Equal(t, 1, missingArgument.Index)
+	}
+}
+
+func TestSetUnknownOptionError(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addErrorsOptions(t, sage)
+	err := sage.Set("missing", "1")
+	var target *usage.UnknownOptionError
+	assert.ErrorAs(t, err, &target)
+	assert.True(t, errors.Is(err, usage.ErrOptionNotFound))
+	assert.Equal(t, -1, target.Index)
+}
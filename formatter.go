@@ -0,0 +1,71 @@
+package usage
+
+import (
+	"io"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// Formatter renders usage information, errors and warnings from a read-only
+// Model of the command line interface. Implement it to produce custom help
+// output and install it with WithCustomFormatter.
+type Formatter = internal.ModelFormatter
+
+// Model is a read-only snapshot of the application metadata and the visible
+// groups, options and arguments, in display order.
+type Model = internal.Model
+
+// ModelGroup is an option group in a Model.
+type ModelGroup = internal.ModelGroup
+
+// Writers holds the output and error writers passed to a Formatter.
+type Writers = internal.Writers
+
+// Example is a sample invocation shown in the usage output.
+type Example = internal.Example
+
+// Section is a custom block of text shown in the usage output.
+type Section = internal.Section
+
+// WithCustomFormatter sets a Formatter implemented outside of this package for
+// usage and error output. Usage information is written to output and errors
+// and warnings to errOutput, which default to os.Stdout and os.Stderr when nil.
+func WithCustomFormatter(formatter Formatter, output, errOutput io.Writer) UsageOption {
+	return func(u *Usage) {
+		u.formatter = &internal.ModelFormatterAdapter{
+			Formatter: formatter,
+			Output:    output,
+			Error:     errOutput,
+		}
+	}
+}
+
+// Model returns a snapshot of the command line interface as it is shown in the
+// usage output. It can be used to render documentation such as man pages.
+func (s *Usage) Model() *Model {
+	s.prepareUsage()
+	return internal.NewModel(s.configuration)
+}
+
+// attachConfiguration sets the configuration of formatters that were created
+// without one, such as those returned by pkg.NewStandardFormatter(out, err, nil).
+func attachConfiguration(formatter internal.Formatter, c *internal.Configuration) {
+	switch f := formatter.(type) {
+	case *internal.StandardFormatter:
+		if f.Configuration == nil {
+			f.Configuration = c
+		}
+	case *internal.ColorFormatter:
+		if f.Configuration == nil {
+			f.Configuration = c
+		}
+	case *internal.JSONFormatter:
+		if f.Configuration == nil {
+			f.Configuration = c
+		}
+	case *internal.ModelFormatterAdapter:
+		if f.Configuration == nil {
+			f.Configuration = c
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/stretchr/testify/assert"
+)
+
+// listFormatter is a Formatter implemented outside of the usage package.
+type listFormatter struct {
+	models []*usage.Model
+}
+
+func (f *listFormatter) FormatUsage(w usage.Writers, model *usage.Model) error {
+	f.models = append(f.models, model)
+	for _, group := range model.Groups {
+		for _, option := range group.Options {
+			fmt.Fprintf(w.Output, "%s --%s\n", group.Name, option.Long)
+		}
+	}
+	return nil
+}
+
+func (f *listFormatter) FormatError(w usage.Writers, model *usage.Model, err error) error {
+	_, e := fmt.Fprintf(w.Error, "%s: %v\n", model.Name, err)
+	return e
+}
+
+func (f *listFormatter) FormatWarning(w usage.Writers, model *usage.Model, message string) error {
+	f.models = append(f.models, model)
+	_, err := fmt.Fprintf(w.Error, "%s: warning: %s\n", model.Name, message)
+	return err
+}
+
+func TestWithCustomFormatter(t *testing.T) {
+	var out, errOut bytes.Buffer
+	formatter := &listFormatter{}
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithCustomFormatter(formatter, &out, &errOut),
+	)
+	_, err := sage.AddStringOptionE("", "out", "", "Output file", "", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, sage.DeprecateOption("out", "renamed", ""))
+
+	assert.NoError(t, sage.ParseArgs([]string{"--out", "a.txt"}))
+	assert.Equal(t, "app: warning: option --out is deprecated: renamed\n", errOut.String())
+	if assert.Len(t, formatter.models, 1) {
+		assert.Equal(t, "app", formatter.models[0].Name)
+	}
+}
+
+func TestModel(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithApplicationVersion("1.2.3"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	group := sage.AddGroup(1, "Network", "Network options")
+	sage.AddIntegerOption("p", "port", 80, "Port", "", group)
+	sage.AddBooleanOption("v", "verbose", false, "Verbose output", "", nil)
+	sage.AddStringOption("", "debug-dump", "", "Internal", "", nil)
+	assert.NoError(t, sage.HideOption("debug-dump"))
+	sage.AddArgument(1, "host", "Target host", "")
+
+	model := sage.Model()
+	assert.Equal(t, "app", model.Name)
+	assert.Equal(t, "1.2.3", model.Version)
+	if assert.Len(t, model.Groups, 2) {
+		assert.Equal(t, "Default", model.Groups[0].Name)
+		assert.Len(t, model.Groups[0].Options, 1)
+		assert.Equal(t, "Network", model.Groups[1].Name)
+		assert.Equal(t, "port", model.Groups[1].Options[0].Long)
+	}
+	if assert.Len(t, model.Arguments, 1) {
+		assert.Equal(t, "host", model.Arguments[0].Name)
+	}
+
+	model.Groups[1].Options[0].Description = "changed"
+	assert.Equal(t, "Port", sage.LookupOption("port").Description)
+}
+
+func TestModelBuiltinOptions(t *testing.T) {
+	sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+	sage.String("token").Secret().Var(new(string))
+
+	var names []string
+	for _, group := range sage.Model().Groups {
+		for _, option := range group.Options {
+			names = append(names, option.Long)
+		}
+	}
+	assert.Equal(t, []string{"token", "version", "token-file"}, names)
+}
+
+func TestWithErrorMode(t *testing.T) {
+	sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+	assert.Equal(t, usage.ErrorMode(""), sage.Model().ErrorMode)
+
+	sage = usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithErrorMode(usage.ERROR_MODE_FULL),
+	)
+	assert.Equal(t, usage.ERROR_MODE_FULL, sage.Model().ErrorMode)
+}
@@ -0,0 +1,160 @@
+package usage
+
+// Source identifies where option values were applied from, see OnSourceApplied.
+type Source string
+
+const (
+	// SOURCE_COMMAND_LINE is the command line, including response files.
+	SOURCE_COMMAND_LINE Source = "command line"
+
+	// SOURCE_SECRET_FILES are the files named by the -file options of secret
+	// options.
+	SOURCE_SECRET_FILES Source = "secret files"
+
+	// SOURCE_ENVIRONMENT are the environment variables of the options.
+	SOURCE_ENVIRONMENT Source = "environment"
+
+	// SOURCE_PROFILE is the profile selected from the configuration files, see
+	// WithProfiles.
+	SOURCE_PROFILE Source = "profile"
+
+	// SOURCE_CONFIG_FILE are the settings of the configuration files, see
+	// WithConfigFiles.
+	SOURCE_CONFIG_FILE Source = "config file"
+
+	// SOURCE_PROMPT are the answers to the prompts for missing required values.
+	SOURCE_PROMPT Source = "prompt"
+)
+
+// hooks holds the lifecycle hooks registered on a Usage, in registration
+// order.
+type hooks struct {
+	beforeParse   []func(u *Usage, args []string) error
+	sourceApplied []func(u *Usage, source Source) error
+	validated     []func(u *Usage) error
+	beforeRun     []func(u *Usage) error
+	afterRun      []func(u *Usage, err error) error
+}
+
+// WithParent makes the Usage a child of parent, such as a subcommand that
+// parses its own arguments with a separate flag set. The hooks of parent,
+// and of its own parents, are run together with the hooks of the child:
+// parents first, except for the after-run hooks which run children first.
+// Hooks added to parent later are taken into account as well.
+func WithParent(parent *Usage) UsageOption {
+	return func(u *Usage) {
+		u.parent = parent
+	}
+}
+
+// OnBeforeParse adds a hook that is called by ParseArgs with the arguments,
+// before response files are expanded or any option is parsed. Returning an
+// error aborts parsing and ParseArgs returns the error.
+func (s *Usage) OnBeforeParse(hook func(u *Usage, args []string) error) {
+	s.hooks.beforeParse = append(s.hooks.beforeParse, hook)
+}
+
+// OnSourceApplied adds a hook that is called by ParseArgs after the values of
+// each source have been applied, in order of precedence: SOURCE_COMMAND_LINE,
+// SOURCE_SECRET_FILES, SOURCE_ENVIRONMENT, SOURCE_PROFILE when profiles are
+// enabled, SOURCE_CONFIG_FILE when configuration files are used and
+// SOURCE_PROMPT when prompting is enabled. The hook is called for each source
+// whether or not it set any value, IsSet and the option values reflect the
+// sources applied so far. Returning an error aborts parsing and ParseArgs
+// returns the error.
+func (s *Usage) OnSourceApplied(hook func(u *Usage, source Source) error) {
+	s.hooks.sourceApplied = append(s.hooks.sourceApplied, hook)
+}
+
+// OnValidated adds a hook that is called by ParseArgs once all values have
+// been applied and validated successfully, e.g. to initialize logging or to
+// check settings that depend on each other. Returning an error makes
+// ParseArgs return the error, so Parse prints it through the formatter.
+func (s *Usage) OnValidated(hook func(u *Usage) error) {
+	s.hooks.validated = append(s.hooks.validated, hook)
+}
+
+// OnBeforeRun adds a hook that is called by Invoke before the run function.
+// Returning an error aborts the run: neither the run function nor the
+// after-run hooks are called and Invoke returns the error.
+func (s *Usage) OnBeforeRun(hook func(u *Usage) error) {
+	s.hooks.beforeRun = append(s.hooks.beforeRun, hook)
+}
+
+// OnAfterRun adds a hook that is called by Invoke after the run function with
+// the error it returned, e.g. to clean up. All after-run hooks are called even
+// if one of them fails, in the reverse order in which they were added.
+func (s *Usage) OnAfterRun(hook func(u *Usage, err error) error) {
+	s.hooks.afterRun = append(s.hooks.afterRun, hook)
+}
+
+// Invoke calls run between the before-run and after-run hooks. It returns the
+// error of the first before-run hook that fails, otherwise the error of run
+// or, if run succeeded, the first error returned by an after-run hook.
+func (s *Usage) Invoke(run func() error) error {
+	for _, u := range s.lineage() {
+		for _, hook := range u.hooks.beforeRun {
+			if err := hook(s); err != nil {
+				return err
+			}
+		}
+	}
+
+	err := run()
+	lineage := s.lineage()
+	for i := len(lineage) - 1; i >= 0; i-- {
+		hooks := lineage[i].hooks.afterRun
+		for j := len(hooks) - 1; j >= 0; j-- {
+			if hookErr := hooks[j](s, err); hookErr != nil && err == nil {
+				err = hookErr
+			}
+		}
+	}
+	return err
+}
+
+// lineage returns the parents of the Usage, outermost first, followed by the
+// Usage itself.
+func (s *Usage) lineage() []*Usage {
+	var lineage []*Usage
+	for u := s; u != nil; u = u.parent {
+		lineage = append([]*Usage{u}, lineage...)
+	}
+	return lineage
+}
+
+// runBeforeParse calls the before-parse hooks of the lineage with args.
+func (s *Usage) runBeforeParse(args []string) error {
+	for _, u := range s.lineage() {
+		for _, hook := range u.hooks.beforeParse {
+			if err := hook(s, args); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runSourceApplied calls the source hooks of the lineage for source.
+func (s *Usage) runSourceApplied(source Source) error {
+	for _, u := range s.lineage() {
+		for _, hook := range u.hooks.sourceApplied {
+			if err := hook(s, source); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runValidated calls the validation hooks of the lineage.
+func (s *Usage) runValidated() error {
+	for _, u := range s.lineage() {
+		for _, hook := range u.hooks.validated {
+			if err := hook(s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package usage_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordHooks adds hooks to sage that append their calls, prefixed with name,
+// to calls.
+func recordHooks(sage *usage.Usage, name string, calls *[]string) {
+	sage.OnBeforeParse(func(u *usage.Usage, args []string) error {
+		*calls = append(*calls, fmt.Sprintf("%s before parse %v", name, args))
+		return nil
+	})
+	sage.OnSourceApplied(func(u *usage.Usage, source usage.Source) error {
+		*calls = append(*calls, fmt.Sprintf("%s %s", name, source))
+		return nil
+	})
+	sage.OnValidated(func(u *usage.Usage) error {
+		*calls = append(*calls, name+" validated")
+		return nil
+	})
+	sage.OnBeforeRun(func(u *usage.Usage) error {
+		*calls = append(*calls, name+" before run")
+		return nil
+	})
+	sage.OnAfterRun(func(u *usage.Usage, err error) error {
+		*calls = append(*calls, fmt.Sprintf("%s after run %v", name, err))
+		return nil
+	})
+}
+
+func TestHookOrder(t *testing.T) {
+	var calls []string
+	parent := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	child := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithParent(parent),
+	)
+	recordHooks(parent, "parent", &calls)
+	recordHooks(child, "child", &calls)
+
+	assert.NoError(t, child.ParseArgs([]string{"x"}))
+	runErr := errors.New("failed")
+	err := child.Invoke(func() error {
+		calls = append(calls, "run")
+		return runErr
+	})
+	assert.Equal(t, runErr, err)
+	assert.Equal(t, []string{
+		"parent before parse [x]",
+		"child before parse [x]",
+		"parent command line",
+		"child command line",
+		"parent secret files",
+		"child secret files",
+		"parent environment",
+		"child environment",
+		"parent validated",
+		"child validated",
+		"parent before run",
+		"child before run",
+		"run",
+		"child after run failed",
+		"parent after run failed",
+	}, calls)
+}
+
+func TestHookSourceValues(t *testing.T) {
+	t.Setenv("HOOK_LEVEL", "debug")
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	level, err := sage.String("level").Env("HOOK_LEVEL").Build()
+	assert.NoError(t, err)
+	name, err := sage.String("name").Build()
+	assert.NoError(t, err)
+
+	values := map[usage.Source]string{}
+	sage.OnSourceApplied(func(u *usage.Usage, source usage.Source) error {
+		values[source] = *name + "/" + *level
+		return nil
+	})
+	assert.NoError(t, sage.ParseArgs([]string{"--name", "app"}))
+	assert.Equal(t, "app/", values[usage.SOURCE_COMMAND_LINE])
+	assert.Equal(t, "app/debug", values[usage.SOURCE_ENVIRONMENT])
+}
+
+func TestHookAbort(t *testing.T) {
+	abort := errors.New("abort")
+	tests := []struct {
+		name string
+		hook func(sage *usage.Usage)
+	}{
+		{
+			name: "before parse",
+			hook: func(sage *usage.Usage) {
+				sage.OnBeforeParse(func(*usage.Usage, []string) error { return abort })
+			},
+		},
+		{
+			name: "source applied",
+			hook: func(sage *usage.Usage) {
+				sage.OnSourceApplied(func(u *usage.Usage, source usage.Source) error {
+					if source == usage.SOURCE_ENVIRONMENT {
+						return abort
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name: "validated",
+			hook: func(sage *usage.Usage) {
+				sage.OnValidated(func(*usage.Usage) error { return abort })
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sage := usage.NewUsage(
+				usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+				usage.WithoutVersionFlag(),
+				usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+			)
+			validated := false
+			tt.hook(sage)
+			sage.OnValidated(func(*usage.Usage) error {
+				validated = true
+				return nil
+			})
+			assert.Equal(t, abort, sage.ParseArgs(nil))
+			assert.False(t, validated)
+		})
+	}
+}
+
+func TestHookSkippedOnParseError(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	_, err := sage.Int("count").Build()
+	assert.NoError(t, err)
+	validated := false
+	sage.OnValidated(func(*usage.Usage) error {
+		validated = true
+		return nil
+	})
+	assert.Error(t, sage.ParseArgs([]string{"--count", "x"}))
+	assert.False(t, validated)
+}
+
+func TestInvokeHookErrors(t *testing.T) {
+	abort := errors.New("abort")
+	cleanup := errors.New("cleanup")
+
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	ran, cleaned := false, 0
+	sage.OnBeforeRun(func(*usage.Usage) error { return abort })
+	sage.OnAfterRun(func(*usage.Usage, error) error {
+		cleaned++
+		return nil
+	})
+	assert.Equal(t, abort, sage.Invoke(func() error {
+		ran = true
+		return nil
+	}))
+	assert.False(t, ran)
+	assert.Equal(t, 0, cleaned)
+
+	sage = usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	sage.OnAfterRun(func(*usage.Usage, error) error {
+		cleaned++
+		return nil
+	})
+	sage.OnAfterRun(func(*usage.Usage, error) error { return cleanup })
+	assert.Equal(t, cleanup, sage.Invoke(func() error { return nil }))
+	assert.Equal(t, 1, cleaned)
+}
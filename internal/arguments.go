@@ -7,4 +7,14 @@ type Argument struct {
 	Name        string // Name of the argument shown in usage output
 	Description string // Help text describing the argument
 	Extra       string // Additional information shown in usage output
+	Required    bool   // Argument must be provided
+}
+
+// HelpDescription returns the description shown in usage output, annotated
+// with whether the argument is required.
+func (a *Argument) HelpDescription() string {
+	if a.Required {
+		return a.Description + " (required)"
+	}
+	return a.Description
 }
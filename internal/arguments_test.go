@@ -50,3 +50,12 @@ func TestArgument_Creation(t *testing.T) {
 		})
 	}
 }
+
+func TestArgument_HelpDescription(t *testing.T) {
+	if got := (&Argument{Description: "Source file"}).HelpDescription(); got != "Source file" {
+		t.Errorf("HelpDescription() = %q, want %q", got, "Source file")
+	}
+	if got := (&Argument{Description: "Source file", Required: true}).HelpDescription(); got != "Source file (required)" {
+		t.Errorf("HelpDescription() = %q, want %q", got, "Source file (required)")
+	}
+}
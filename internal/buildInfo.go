@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"runtime/debug"
+	"strconv"
+)
+
+// ApplyBuildInfo fills the empty version, commit hash and build date of the
+// configuration from the module version and the vcs.* settings recorded by the
+// Go toolchain, and records whether the working tree was modified. Values that
+// were set explicitly are never overwritten. The module version "(devel)",
+// used for builds from a local checkout, is ignored.
+func ApplyBuildInfo(c *Configuration, info *debug.BuildInfo) {
+	if info == nil {
+		return
+	}
+
+	if c.ApplicationVersion == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		c.ApplicationVersion = info.Main.Version
+	}
+
+	explicitCommit := c.ApplicationCommitHash != ""
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if !explicitCommit {
+				c.ApplicationCommitHash = setting.Value
+			}
+		case "vcs.time":
+			if c.ApplicationBuildDate == "" {
+				c.ApplicationBuildDate = setting.Value
+			}
+		case "vcs.modified":
+			if !explicitCommit {
+				c.ApplicationModified, _ = strconv.ParseBool(setting.Value)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestApplyBuildInfo(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/myapp", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "0123456789abcdef"},
+			{Key: "vcs.time", Value: "2024-01-15T10:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		config       Configuration
+		info         *debug.BuildInfo
+		wantVersion  string
+		wantCommit   string
+		wantDate     string
+		wantModified bool
+	}{
+		{
+			name:         "empty configuration",
+			info:         info,
+			wantVersion:  "v1.2.3",
+			wantCommit:   "0123456789abcdef",
+			wantDate:     "2024-01-15T10:00:00Z",
+			wantModified: true,
+		},
+		{
+			name: "explicit values are kept",
+			config: Configuration{
+				ApplicationVersion:    "2.0.0",
+				ApplicationCommitHash: "abc123",
+				ApplicationBuildDate:  "2024-02-01",
+			},
+			info:         info,
+			wantVersion:  "2.0.0",
+			wantCommit:   "abc123",
+			wantDate:     "2024-02-01",
+			wantModified: false,
+		},
+		{
+			name: "devel version is ignored",
+			info: &debug.BuildInfo{
+				Main: debug.Module{Version: "(devel)"},
+			},
+		},
+		{
+			name: "no build info",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			ApplyBuildInfo(&config, tt.info)
+
+			if config.ApplicationVersion != tt.wantVersion {
+				t.Errorf("ApplicationVersion = %q, want %q", config.ApplicationVersion, tt.wantVersion)
+			}
+			if config.ApplicationCommitHash != tt.wantCommit {
+				t.Errorf("ApplicationCommitHash = %q, want %q", config.ApplicationCommitHash, tt.wantCommit)
+			}
+			if config.ApplicationBuildDate != tt.wantDate {
+				t.Errorf("ApplicationBuildDate = %q, want %q", config.ApplicationBuildDate, tt.wantDate)
+			}
+			if config.ApplicationModified != tt.wantModified {
+				t.Errorf("ApplicationModified = %v, want %v", config.ApplicationModified, tt.wantModified)
+			}
+		})
+	}
+}
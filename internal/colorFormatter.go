@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"sort"
 
 	"github.com/fatih/color"
 )
@@ -12,33 +11,50 @@ import (
 // ColorFormatter implements the Formatter interface using ANSI color codes
 // to enhance the visual appearance of usage output. It uses the fatih/color
 // package to provide colored text for different sections of the usage display.
+// Colors are only emitted when ColorEnabled reports that the destination writer
+// supports them, otherwise the same layout is written as plain text.
 type ColorFormatter struct {
 	Output        io.Writer      // Writer for normal usage output (defaults to os.Stdout)
 	Error         io.Writer      // Writer for error messages (defaults to os.Stderr)
 	Configuration *Configuration // Application and option configuration
+	Theme         *Theme         // Styles used for each element (defaults to DefaultTheme)
+}
+
+// theme returns the configured theme or the DefaultTheme when none is set.
+func (f *ColorFormatter) theme() *Theme {
+	if f.Theme == nil {
+		return DefaultTheme()
+	}
+	return f.Theme
 }
 
 // PrintUsage outputs formatted usage information with ANSI color codes.
 // It displays application metadata (version, build date, commit hash),
-// description, options grouped by category, and positional arguments.
+// description, options grouped by category, positional arguments, examples
+// and any custom sections at their configured positions.
 // If Output is nil, it defaults to os.Stdout.
 func (f *ColorFormatter) PrintUsage() {
 	if f.Output == nil {
 		f.Output = os.Stdout
 	}
 
-	// Define colors
-	lineColor := color.New(color.FgHiWhite)
-	usageColor := color.New(color.FgHiBlue, color.Bold)
-	headerColor := color.New(color.FgHiBlue, color.Bold)
-	optionHeaderColor := color.New(color.FgHiBlue)
-	optionDescColor := color.New(color.FgWhite)
-	optionColor := color.New(color.FgGreen)
-	optionDefaultColor := color.New(color.FgHiCyan)
+	// Resolve the theme colors for the output writer
+	theme := f.theme()
+	enabled := ColorEnabled(f.Output)
+	lineColor := theme.Text.painter(enabled)
+	usageColor := theme.Usage.painter(enabled)
+	headerColor := theme.Header.painter(enabled)
+	optionHeaderColor := theme.GroupHeader.painter(enabled)
+	optionDescColor := theme.Description.painter(enabled)
+	optionColor := theme.Option.painter(enabled)
+	optionDefaultColor := theme.Default.painter(enabled)
 
 	// Print the usage line with colors
 	usageColor.Fprint(f.Output, "Usage: ")
-	lineColor.Fprintf(f.Output, "%s [OPTIONS] [ARGUMENTS]\n\n", f.Configuration.ApplicationName)
+	lineColor.Fprintf(f.Output, "%s\n\n", Synopsis(f.Configuration))
+
+	// Print any custom sections placed at the top
+	f.printSections(f.Configuration.SectionsAt(SectionTop), headerColor, lineColor)
 
 	// Print the version information if it is provided
 	versionInfoPresent := false
@@ -58,22 +74,16 @@ func (f *ColorFormatter) PrintUsage() {
 	// Print the commit hash information if it is provided
 	if f.Configuration.ApplicationCommitHash != "" {
 		headerColor.Fprintf(f.Output, "Codebase: ")
-		lineColor.Fprintf(f.Output, "%s", f.Configuration.ApplicationCommitHash)
-		if f.Configuration.ApplicationBranch != "" {
-			lineColor.Fprintf(f.Output, " (%s)", f.Configuration.ApplicationBranch)
-		}
-		fmt.Fprintln(f.Output, "")
+		lineColor.Fprintf(f.Output, "%s%s\n", f.Configuration.ApplicationCommitHash, codebaseDetails(f.Configuration))
 		versionInfoPresent = true
 	}
 	if versionInfoPresent {
 		fmt.Fprintln(f.Output, "")
 	}
 
-	// Wrap the description text
-	wrappedDescription := wrapText(f.Configuration.ApplicationDescription, 60, len("Description: "))
-
 	// Print the description if one is provided
-	if len(wrappedDescription) > 0 {
+	if f.Configuration.ApplicationDescription != "" {
+		wrappedDescription := wrapText(f.Configuration.ApplicationDescription, 60, len("Description: "))
 		headerColor.Fprint(f.Output, "Description: ")
 		lineColor.Fprintf(f.Output, "%s\n", wrappedDescription[0])
 		for _, line := range wrappedDescription[1:] {
@@ -82,67 +92,135 @@ func (f *ColorFormatter) PrintUsage() {
 		fmt.Fprintln(f.Output, "")
 	}
 
-	// Get all the keys for the groups so we can get the priorities and then sort them
-	groupKeys := make([]string, 0, len(f.Configuration.Groups))
-	for key := range f.Configuration.Groups {
-		groupKeys = append(groupKeys, key)
-	}
-	sort.Slice(groupKeys, func(i, j int) bool {
-		return f.Configuration.Groups[groupKeys[i]].Priority < f.Configuration.Groups[groupKeys[j]].Priority
-	})
+	// Print any custom sections placed before the options
+	f.printSections(f.Configuration.SectionsAt(SectionBeforeOptions), headerColor, lineColor)
 
 	headerColor.Fprintln(f.Output, "Options:")
-	for _, key := range groupKeys {
-		group := f.Configuration.Groups[key]
-		if len(group.Options) == 0 {
+	for _, group := range f.Configuration.SortedGroups() {
+		options := group.VisibleOptions(f.Configuration.ShowAll)
+		if len(options) == 0 {
 			continue
 		}
-		sw, lw, dvw, dsw := group.CalculateOptionWidths()
+		hasShort, nameWidth, defaultWidth := optionColumnWidths(options)
+
 		optionHeaderColor.Fprintf(f.Output, "  %s: ", group.Name)
 		lineColor.Fprintf(f.Output, "%s\n", group.Description)
-		for _, option := range group.Options {
-			optionColor.Fprintf(f.Output, "    -%-*s --%-*s", sw, option.Short, lw, option.Long)
-			optionDefault := option.Default
-			if optionDefault == "" {
-				optionDefault = "-"
+		for _, option := range options {
+			optionColor.Fprintf(f.Output, "    %-*s", nameWidth, optionNames(option, hasShort))
+			description := option.HelpDescription()
+			if description == "" {
+				optionDefaultColor.Fprintf(f.Output, "  %s\n", optionDefault(option))
+				continue
 			}
-			optionDefaultColor.Fprintf(f.Output, "  %-*v", dvw, optionDefault)
-			optionDescColor.Fprintf(f.Output, "  %-*s\n", dsw, option.Description)
+			optionDefaultColor.Fprintf(f.Output, "  %-*s", defaultWidth, optionDefault(option))
+			optionDescColor.Fprintf(f.Output, "  %s\n", description)
 		}
 		fmt.Fprintln(f.Output, "")
 	}
 
-	// Get a count of the number of arguments
-	argumentCount := 0
-	for _, group := range f.Configuration.Groups {
-		argumentCount += len(group.Arguments)
+	// Print the positional arguments ordered by position
+	arguments := f.Configuration.SortedArguments()
+	if len(arguments) > 0 {
+		headerColor.Fprintln(f.Output, "Arguments:")
+		nameWidth := argumentNameWidth(arguments)
+		for _, argument := range arguments {
+			description := argument.HelpDescription()
+			if description == "" {
+				optionColor.Fprintf(f.Output, "    %s\n", argument.Name)
+				continue
+			}
+			optionColor.Fprintf(f.Output, "    %-*s", nameWidth, argument.Name)
+			optionDescColor.Fprintf(f.Output, "  %s\n", description)
+		}
 	}
 
-	if argumentCount > 0 {
-		headerColor.Fprintln(f.Output, "Arguments:")
-		for _, group := range f.Configuration.Groups {
-			arguments := group.Arguments
-			// Sort the arguments by position
-			sort.Slice(arguments, func(i, j int) bool {
-				return arguments[i].Position < arguments[j].Position
-			})
-			for _, argument := range arguments {
-				optionColor.Fprintf(f.Output, "    %s", argument.Name)
-				optionDescColor.Fprintf(f.Output, "  %s\n", argument.Description)
+	// Print the examples and footer sections
+	examples := f.Configuration.Examples
+	footers := f.Configuration.SectionsAt(SectionBottom)
+	if len(arguments) > 0 && (len(examples) > 0 || len(footers) > 0) {
+		fmt.Fprintln(f.Output, "")
+	}
+	if len(examples) > 0 {
+		headerColor.Fprintln(f.Output, "Examples:")
+		for _, example := range examples {
+			optionColor.Fprintf(f.Output, "    %s\n", example.Command)
+			if example.Explanation != "" {
+				optionDescColor.Fprintf(f.Output, "        %s\n", example.Explanation)
 			}
 		}
+		fmt.Fprintln(f.Output, "")
 	}
+	f.printSections(footers, headerColor, lineColor)
 }
 
-// PrintError outputs the error message in red followed by the usage information.
-// If Error is nil, it defaults to os.Stderr. The error message is displayed
-// after the usage information to ensure the user sees both.
+// printSections outputs custom sections with their title as a header and the
+// body indented below it, each followed by a blank line.
+func (f *ColorFormatter) printSections(sections []*Section, headerColor, lineColor *color.Color) {
+	for _, section := range sections {
+		headerColor.Fprintf(f.Output, "%s:\n", section.Title)
+		for _, line := range section.Lines() {
+			lineColor.Fprintf(f.Output, "  %s\n", line)
+		}
+		fmt.Fprintln(f.Output, "")
+	}
+}
+
+// PrintError outputs the error message in red to the error writer, followed
+// by the command line with the offending token marked if the error refers to
+// one. Each error of an ErrorList is printed in turn. By default the errors
+// are followed by any suggestions, the usage line and a hint to run --help.
+// With ErrorModeFull the complete usage information is printed before the
+// errors, so that the errors are the last thing the user sees. Nothing is
+// printed if err is nil or an empty ErrorList.
+// If Error is nil, it defaults to os.Stderr.
 func (f *ColorFormatter) PrintError(err error) {
 	if f.Error == nil {
 		f.Error = os.Stderr
 	}
 
-	f.PrintUsage()
-	errColor := color.New(color.FgHiRed)
-	errColor.Fprintln(f.Error, "[!] Error: ", err)
+	usageErrs := UsageErrors(err)
+	if len(usageErrs) == 0 {
+		return
+	}
+	theme := f.theme()
+	enabled := ColorEnabled(f.Error)
+	errColor := theme.Error.painter(enabled)
+	full := f.Configuration.ErrorMode == ErrorModeFull
+	if full {
+		f.PrintUsage()
+	}
+
+	for _, usageErr := range usageErrs {
+		if full {
+			errColor.Fprintln(f.Error, "[!] Error: ", usageErr)
+		} else {
+			errColor.Fprintf(f.Error, "[!] Error: %s\n", usageErr.Summary())
+		}
+		if lines := usageErr.Highlight(f.Configuration.ApplicationName); lines != nil {
+			theme.Text.painter(enabled).Fprintf(f.Error, "    %s\n", lines[0])
+			errColor.Fprintf(f.Error, "    %s\n", lines[1])
+		}
+		if hint := usageErr.SuggestionHint(); hint != "" && !full {
+			theme.Option.painter(enabled).Fprintln(f.Error, "    "+capitalize(hint))
+		}
+	}
+	if full {
+		return
+	}
+
+	fmt.Fprintln(f.Error, "")
+	theme.Usage.painter(enabled).Fprint(f.Error, "Usage: ")
+	theme.Text.painter(enabled).Fprintf(f.Error, "%s\n", Synopsis(f.Configuration))
+	theme.Text.painter(enabled).Fprintln(f.Error, HelpHint(f.Configuration))
+}
+
+// PrintWarning outputs a warning message, such as the use of a deprecated
+// option, to the error writer. If Error is nil, it defaults to os.Stderr.
+func (f *ColorFormatter) PrintWarning(message string) {
+	if f.Error == nil {
+		f.Error = os.Stderr
+	}
+
+	warnColor := f.theme().Warning.painter(ColorEnabled(f.Error))
+	warnColor.Fprintf(f.Error, "[!] Warning: %s\n", message)
 }
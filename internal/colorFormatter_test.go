@@ -5,6 +5,8 @@ import (
 	"errors"
 	"strings"
 	"testing"
+
+	"github.com/fatih/color"
 )
 
 func TestColorFormatter_PrintUsage(t *testing.T) {
@@ -132,11 +134,45 @@ func TestColorFormatter_PrintUsage(t *testing.T) {
 	}
 }
 
+func TestColorFormatter_MatchesStandardLayout(t *testing.T) {
+	clearColorEnv(t)
+	config := &Configuration{
+		ApplicationName: "app",
+		Groups: map[string]*Group{
+			"Default": {
+				Name:        "Default",
+				Description: "Default Options",
+				Options: []*Option{
+					{Short: "v", Long: "verbose", Default: false, Description: "Verbose output"},
+					{Long: "output", Default: "out.txt"},
+				},
+				Arguments: []*Argument{
+					{Position: 1, Name: "src", Description: "Source"},
+					{Position: 2, Name: "destination"},
+					{Position: 3, Name: "mode", Description: "Copy mode"},
+				},
+			},
+		},
+	}
+
+	var standard, colored bytes.Buffer
+	(&StandardFormatter{Output: &standard, Configuration: config}).PrintUsage()
+	(&ColorFormatter{Output: &colored, Configuration: config}).PrintUsage()
+	if colored.String() != standard.String() {
+		t.Errorf("PrintUsage() =\n%s\nwant the layout of StandardFormatter:\n%s", colored.String(), standard.String())
+	}
+	if strings.Contains(colored.String(), "Description:") {
+		t.Errorf("PrintUsage() printed an empty description:\n%s", colored.String())
+	}
+}
+
 func TestColorFormatter_PrintError(t *testing.T) {
 	tests := []struct {
 		name           string
+		mode           ErrorMode
 		err            error
 		expectedOutput []string
+		notExpected    []string
 	}{
 		{
 			name: "simple error",
@@ -144,7 +180,33 @@ func TestColorFormatter_PrintError(t *testing.T) {
 			expectedOutput: []string{
 				"Error:",
 				"test error",
+				"Usage: testapp [OPTIONS] [ARGUMENTS]",
+				"Run 'testapp --help' for more information.",
+			},
+			notExpected: []string{"Options:"},
+		},
+		{
+			name: "suggestions",
+			err: &UsageError{
+				Kind:        ErrorKindUnknownOption,
+				Message:     "flag provided but not defined: -verbos",
+				Suggestions: []string{"--verbose"},
+			},
+			expectedOutput: []string{
+				"[!] Error: flag provided but not defined: -verbos\n",
+				"Did you mean --verbose?",
+			},
+			notExpected: []string{"Options:"},
+		},
+		{
+			name: "full usage",
+			mode: ErrorModeFull,
+			err:  errors.New("test error"),
+			expectedOutput: []string{
+				"Options:",
+				"test error",
 			},
+			notExpected: []string{"--help' for more"},
 		},
 	}
 
@@ -154,6 +216,7 @@ func TestColorFormatter_PrintError(t *testing.T) {
 			config := &Configuration{
 				ApplicationName: "testapp",
 				Groups:          map[string]*Group{},
+				ErrorMode:       tt.mode,
 			}
 			formatter := &ColorFormatter{
 				Output:        &buf,
@@ -169,6 +232,11 @@ func TestColorFormatter_PrintError(t *testing.T) {
 					t.Errorf("PrintError() output missing expected substring %q", expected)
 				}
 			}
+			for _, unexpected := range tt.notExpected {
+				if strings.Contains(output, unexpected) {
+					t.Errorf("PrintError() output contains unexpected substring %q", unexpected)
+				}
+			}
 		})
 	}
 }
@@ -199,3 +267,101 @@ func TestColorFormatter_DefaultWriters(t *testing.T) {
 		}
 	})
 }
+
+func TestColorFormatter_Theme(t *testing.T) {
+	config := &Configuration{
+		ApplicationName: "testapp",
+		Groups:          map[string]*Group{},
+	}
+
+	t.Run("plain output for non terminal writer", func(t *testing.T) {
+		clearColorEnv(t)
+		var buf bytes.Buffer
+		formatter := &ColorFormatter{Output: &buf, Configuration: config}
+		formatter.PrintUsage()
+		if strings.Contains(buf.String(), "\x1b[") {
+			t.Errorf("PrintUsage() wrote ANSI codes to a non terminal writer: %q", buf.String())
+		}
+	})
+
+	t.Run("custom theme is used when color is forced", func(t *testing.T) {
+		clearColorEnv(t)
+		t.Setenv("FORCE_COLOR", "1")
+		var buf bytes.Buffer
+		theme := MonochromeTheme()
+		theme.Usage = Style{color.FgMagenta}
+		formatter := &ColorFormatter{Output: &buf, Configuration: config, Theme: theme}
+		formatter.PrintUsage()
+		if !strings.Contains(buf.String(), "\x1b[35mUsage: ") {
+			t.Errorf("PrintUsage() did not use the theme usage style: %q", buf.String())
+		}
+	})
+
+	t.Run("no color disables the error style", func(t *testing.T) {
+		clearColorEnv(t)
+		t.Setenv("NO_COLOR", "1")
+		var buf bytes.Buffer
+		formatter := &ColorFormatter{Output: &buf, Error: &buf, Configuration: config}
+		formatter.PrintError(errors.New("boom"))
+		if strings.Contains(buf.String(), "\x1b[") {
+			t.Errorf("PrintError() wrote ANSI codes with NO_COLOR set: %q", buf.String())
+		}
+	})
+}
+
+func TestColorFormatter_ExamplesAndSections(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := &ColorFormatter{
+		Output: &buf,
+		Configuration: &Configuration{
+			ApplicationName: "myapp",
+			Groups:          map[string]*Group{},
+			Examples:        []*Example{{Command: "myapp -v", Explanation: "Run verbosely"}},
+			Sections: []*Section{
+				{Title: "Environment", Body: "MYAPP_HOME", Position: SectionBeforeOptions},
+				{Title: "Bugs", Body: "https://example.com/bugs", Position: SectionBottom},
+			},
+		},
+	}
+
+	formatter.PrintUsage()
+	output := buf.String()
+
+	for _, expected := range []string{"Examples:", "myapp -v", "Run verbosely", "Environment:", "Bugs:", "https://example.com/bugs"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("PrintUsage() output missing expected substring %q", expected)
+		}
+	}
+	if strings.Index(output, "Environment:") > strings.Index(output, "Options:") {
+		t.Error("PrintUsage() printed the before options section after the options")
+	}
+	if strings.Index(output, "Bugs:") < strings.Index(output, "Examples:") {
+		t.Error("PrintUsage() printed the bottom section before the examples")
+	}
+}
+
+func TestColorFormatter_PrintErrorNone(t *testing.T) {
+	for _, err := range []error{nil, ErrorList{}} {
+		var outBuf, errBuf bytes.Buffer
+		formatter := &ColorFormatter{Output: &outBuf, Error: &errBuf, Configuration: &Configuration{}}
+		formatter.PrintError(err)
+		if outBuf.Len() != 0 || errBuf.Len() != 0 {
+			t.Errorf("PrintError(%#v) wrote %q and %q, want nothing", err, outBuf.String(), errBuf.String())
+		}
+	}
+}
+
+func TestColorFormatter_PrintWarning(t *testing.T) {
+	clearColorEnv(t)
+	var outBuf, errBuf bytes.Buffer
+	formatter := &ColorFormatter{Output: &outBuf, Error: &errBuf, Configuration: &Configuration{}}
+
+	formatter.PrintWarning("option --out is deprecated")
+
+	if want := "[!] Warning: option --out is deprecated\n"; errBuf.String() != want {
+		t.Errorf("PrintWarning() error writer = %q, want %q", errBuf.String(), want)
+	}
+	if outBuf.Len() != 0 {
+		t.Errorf("PrintWarning() wrote to the output writer: %q", outBuf.String())
+	}
+}
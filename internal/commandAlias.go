@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CommandAlias is a user-defined shortcut that stands for a list of
+// arguments, e.g. "co" for "checkout --force".
+type CommandAlias struct {
+	Name      string   // Name of the alias as typed on the command line
+	Expansion string   // Arguments the alias stands for, as written
+	Args      []string // Expansion split into arguments
+	Source    string   // "file:line" of the definition if it was read from a configuration file
+}
+
+// NewCommandAlias returns the alias name for the arguments in expansion,
+// which are split using shell-like quoting. source is the place the alias was
+// defined and is used in error messages.
+func NewCommandAlias(name string, expansion string, source string) (*CommandAlias, error) {
+	args, err := SplitArgs(expansion, source)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("alias %s has an empty expansion", name)
+	}
+	return &CommandAlias{Name: name, Expansion: expansion, Args: args, Source: source}, nil
+}
+
+// SplitArgs splits s into arguments with the shell-like quoting rules of
+// response files. source is the prefix of error messages, e.g. "file:line".
+func SplitArgs(s string, source string) ([]string, error) {
+	tokens, err := tokenize(s, source)
+	var quoteErr *quoteError
+	switch {
+	case errors.As(err, &quoteErr) && source == "":
+		return nil, quoteErr
+	case errors.As(err, &quoteErr):
+		// The line within s is left out as s is a single line
+		return nil, fmt.Errorf("%s: %w", source, quoteErr)
+	case err != nil:
+		return nil, err
+	}
+	args := make([]string, len(tokens))
+	for i, token := range tokens {
+		args[i] = token.Value
+	}
+	return args, nil
+}
+
+// AliasExpander replaces the first positional argument of a command line by
+// the arguments of the alias with that name. Expansions may start with
+// another alias, up to Limit levels deep.
+type AliasExpander struct {
+	Configuration *Configuration           // Options, used to skip option values
+	Normalizer    *ArgNormalizer           // Resolves abbreviated and negated option names, if set
+	Aliases       map[string]*CommandAlias // Aliases by name
+	Limit         int                      // Maximum number of nested expansions
+}
+
+// Expand returns args with the aliases expanded. Aliases after a "--"
+// argument are not expanded. It returns a UsageError if an alias refers to
+// itself, directly or through other aliases, or the expansion is nested more
+// than Limit levels deep.
+func (e *AliasExpander) Expand(args []string) ([]string, error) {
+	tokens, err := e.ExpandTokens(NewTokens(args))
+	if err != nil {
+		return nil, err
+	}
+	return TokenValues(tokens), nil
+}
+
+// ExpandTokens is Expand for arguments that may have been read from response
+// files. The arguments of an alias take the source of the alias name.
+func (e *AliasExpander) ExpandTokens(tokens []Token) ([]Token, error) {
+	var chain []string
+	for {
+		i := e.firstPositional(TokenValues(tokens))
+		if i < 0 {
+			return tokens, nil
+		}
+		alias := e.Aliases[tokens[i].Value]
+		if alias == nil {
+			return tokens, nil
+		}
+		for _, name := range chain {
+			if name == alias.Name {
+				return nil, e.error(alias, fmt.Sprintf("alias loop: %s -> %s", strings.Join(chain, " -> "), alias.Name))
+			}
+		}
+		if len(chain) >= e.Limit {
+			return nil, e.error(alias, fmt.Sprintf("alias expansion exceeds %d levels: %s", e.Limit, strings.Join(chain, " -> ")))
+		}
+		chain = append(chain, alias.Name)
+
+		expanded := append([]Token(nil), tokens[:i]...)
+		for _, arg := range alias.Args {
+			expanded = append(expanded, Token{Value: arg, Source: tokens[i].Source})
+		}
+		tokens = append(expanded, tokens[i+1:]...)
+	}
+}
+
+// error returns a UsageError for a problem with expanding alias.
+func (e *AliasExpander) error(alias *CommandAlias, message string) *UsageError {
+	return &UsageError{
+		Kind:     ErrorKindAlias,
+		Argument: alias.Name,
+		Source:   alias.Source,
+		Message:  message,
+	}
+}
+
+// firstPositional returns the index of the first positional argument in args,
+// skipping options and their values like the flag package, or -1 if there is
+// none before a "--" argument.
+func (e *AliasExpander) firstPositional(args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			return i
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !hasValue && e.takesValue(name, strings.HasPrefix(arg, "--")) {
+			i++
+		}
+	}
+	return -1
+}
+
+// takesValue reports whether the option name is followed by its value. Names
+// the Normalizer resolves stand for the option they resolve to, except for
+// negated options which never take a value. long is whether the name was
+// given with two dashes, as only those may be abbreviated.
+func (e *AliasExpander) takesValue(name string, long bool) bool {
+	option, _ := e.Configuration.FindOption(name)
+	if option == nil && e.Normalizer != nil {
+		resolved, negated, err := e.Normalizer.resolve(name, long)
+		if err != nil || negated {
+			return false
+		}
+		option, _ = e.Configuration.FindOption(resolved)
+	}
+	return option != nil && option.TypeName() != "bool"
+}
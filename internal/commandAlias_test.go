@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	got, err := SplitArgs(`commit -m "work in progress" --author='A B' x\ y`, "")
+	if err != nil {
+		t.Fatalf("SplitArgs() error = %v", err)
+	}
+	want := []string{"commit", "-m", "work in progress", "--author=A B", "x y"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitArgs() = %q, want %q", got, want)
+	}
+
+	for source, message := range map[string]string{
+		"":           "unterminated double quote",
+		"app.ini:3":  "app.ini:3: unterminated double quote",
+		"alias test": "alias test: unterminated double quote",
+	} {
+		if _, err := SplitArgs(`commit -m "wip`, source); err == nil || err.Error() != message {
+			t.Errorf("SplitArgs() with source %q error = %v, want %q", source, err, message)
+		}
+	}
+}
+
+func TestNewCommandAlias(t *testing.T) {
+	alias, err := NewCommandAlias("co", "checkout --force", "app.ini:2")
+	if err != nil {
+		t.Fatalf("NewCommandAlias() error = %v", err)
+	}
+	if !reflect.DeepEqual(alias.Args, []string{"checkout", "--force"}) {
+		t.Errorf("Args = %q", alias.Args)
+	}
+	if _, err := NewCommandAlias("empty", "  ", ""); err == nil {
+		t.Error("NewCommandAlias() with an empty expansion expected error")
+	}
+}
+
+func TestAliasExpander_Expand(t *testing.T) {
+	c := &Configuration{Groups: map[string]*Group{"Default": {Name: "Default"}}}
+	c.Groups["Default"].AddOption(&Option{Short: "o", Long: "output", Default: ""})
+	c.Groups["Default"].AddOption(&Option{Short: "v", Long: "verbose", Default: false})
+
+	aliases := make(map[string]*CommandAlias)
+	for name, expansion := range map[string]string{
+		"co":   "checkout --force",
+		"cof":  "co --quiet",
+		"save": `commit -m "work in progress"`,
+	} {
+		alias, err := NewCommandAlias(name, expansion, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		aliases[name] = alias
+	}
+	expander := &AliasExpander{Configuration: c, Aliases: aliases, Limit: 5}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"first argument", []string{"co", "main"}, []string{"checkout", "--force", "main"}},
+		{"after options", []string{"-v", "-o", "co", "save"}, []string{"-v", "-o", "co", "commit", "-m", "work in progress"}},
+		{"after option with value", []string{"--output=x", "co"}, []string{"--output=x", "checkout", "--force"}},
+		{"nested", []string{"cof"}, []string{"checkout", "--force", "--quiet"}},
+		{"not an alias", []string{"status", "co"}, []string{"status", "co"}},
+		{"after terminator", []string{"--", "co"}, []string{"--", "co"}},
+		{"no positional", []string{"-v"}, []string{"-v"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expander.Expand(tt.args)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasExpander_ExpandNormalized(t *testing.T) {
+	c := &Configuration{Groups: map[string]*Group{"Default": {Name: "Default"}}}
+	c.Groups["Default"].AddOption(&Option{Long: "output", Default: ""})
+	c.Groups["Default"].AddOption(&Option{Long: "verbose", Default: false})
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("output", "", "")
+	fs.Bool("verbose", false, "")
+
+	aliases := map[string]*CommandAlias{"co": {Name: "co", Args: []string{"checkout"}}}
+	tests := []struct {
+		name       string
+		normalizer *ArgNormalizer
+		args       []string
+		want       []string
+	}{
+		{"abbreviation", &ArgNormalizer{FlagSet: fs, Abbreviations: true}, []string{"--out", "f", "co"}, []string{"--out", "f", "checkout"}},
+		{"negation", &ArgNormalizer{FlagSet: fs, Negation: true}, []string{"--no-verbose", "co"}, []string{"--no-verbose", "checkout"}},
+		{"negated abbreviation", &ArgNormalizer{FlagSet: fs, Abbreviations: true, Negation: true}, []string{"--no-verb", "co"}, []string{"--no-verb", "checkout"}},
+		{"abbreviations disabled", &ArgNormalizer{FlagSet: fs}, []string{"--out", "f", "co"}, []string{"--out", "f", "co"}},
+		{"no normalizer", nil, []string{"--out", "f", "co"}, []string{"--out", "f", "co"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expander := &AliasExpander{Configuration: c, Normalizer: tt.normalizer, Aliases: aliases, Limit: 5}
+			got, err := expander.Expand(tt.args)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasExpander_Errors(t *testing.T) {
+	c := &Configuration{}
+	aliases := map[string]*CommandAlias{
+		"a":     {Name: "a", Args: []string{"b"}, Source: "app.ini:1"},
+		"b":     {Name: "b", Args: []string{"a"}, Source: "app.ini:2"},
+		"one":   {Name: "one", Args: []string{"two"}},
+		"two":   {Name: "two", Args: []string{"three"}},
+		"three": {Name: "three", Args: []string{"status"}},
+	}
+	expander := &AliasExpander{Configuration: c, Aliases: aliases, Limit: 2}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"a"}, "app.ini:1: alias loop: a -> b -> a"},
+		{[]string{"one"}, "alias expansion exceeds 2 levels: one -> two"},
+	}
+	for _, tt := range tests {
+		_, err := expander.Expand(tt.args)
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) || usageErr.Kind != ErrorKindAlias {
+			t.Fatalf("Expand(%q) error = %v, want alias UsageError", tt.args, err)
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expand(%q) error = %q, want %q", tt.args, err, tt.want)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "sort"
+
 // Configuration holds all application metadata and option groups.
 // This is the central data structure used by formatters to generate usage output.
 type Configuration struct {
@@ -8,6 +10,96 @@ type Configuration struct {
 	ApplicationBuildDate   string            // Build date/timestamp
 	ApplicationCommitHash  string            // Git commit hash
 	ApplicationBranch      string            // Git branch name
+	ApplicationModified    bool              // Whether the build contains uncommitted changes
 	ApplicationDescription string            // Application description
 	Groups                 map[string]*Group // Option groups keyed by name
+	Examples               []*Example        // Example invocations shown after the arguments
+	Sections               []*Section        // Custom sections such as notes and footers
+	ShowAll                bool              // Show advanced and deprecated options in usage output
+	ErrorMode              ErrorMode         // How errors are presented, ErrorModeConcise when empty
+}
+
+// FindOption returns the option registered with the given short or long name,
+// alias or former name (without dashes) and the group it belongs to, or nil if
+// there is none.
+func (c *Configuration) FindOption(name string) (*Option, *Group) {
+	for _, group := range c.SortedGroups() {
+		for _, option := range group.Options {
+			if option.HasName(name) {
+				return option, group
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Options returns the options of all groups in display order, that is ordered
+// by group as in SortedGroups and in the order they were added within a group.
+func (c *Configuration) Options() []*Option {
+	var options []*Option
+	for _, group := range c.SortedGroups() {
+		options = append(options, group.Options...)
+	}
+	return options
+}
+
+// SectionsAt returns the custom sections that are rendered at the given position
+// in the order they were added.
+func (c *Configuration) SectionsAt(position SectionPosition) []*Section {
+	var sections []*Section
+	for _, section := range c.Sections {
+		if section.Position == position {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// SortedGroups returns the groups ordered by priority (lower numbers first).
+// Groups with the same priority are ordered by name so the result is
+// deterministic regardless of map iteration order.
+func (c *Configuration) SortedGroups() []*Group {
+	groups := make([]*Group, 0, len(c.Groups))
+	for _, group := range c.Groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Priority != groups[j].Priority {
+			return groups[i].Priority < groups[j].Priority
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// SortedArguments returns the positional arguments of all groups ordered by position.
+func (c *Configuration) SortedArguments() []*Argument {
+	var arguments []*Argument
+	for _, group := range c.SortedGroups() {
+		arguments = append(arguments, group.Arguments...)
+	}
+	sort.SliceStable(arguments, func(i, j int) bool {
+		return arguments[i].Position < arguments[j].Position
+	})
+	return arguments
+}
+
+// DeprecationWarning returns the warning for using the option registered under
+// name (without dashes), and true if the option or its group is deprecated.
+func (c *Configuration) DeprecationWarning(name string) (string, bool) {
+	option, group := c.FindOption(name)
+	switch {
+	case option == nil:
+		return "", false
+	case option.IsRenamed(name):
+		renamed := Option{Replacement: option.Name()}
+		return renamed.DeprecationWarning(name), true
+	case option.IsDeprecated():
+		return option.DeprecationWarning(name), true
+	case group.Deprecated != "":
+		deprecated := *option
+		deprecated.Deprecated = group.Deprecated
+		return deprecated.DeprecationWarning(name), true
+	}
+	return "", false
 }
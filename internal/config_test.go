@@ -97,3 +97,162 @@ func TestConfiguration_GroupsMap(t *testing.T) {
 		}
 	})
 }
+
+func TestConfiguration_SortedGroups(t *testing.T) {
+	config := Configuration{
+		Groups: map[string]*Group{
+			"b":       {Priority: 1, Name: "b"},
+			"a":       {Priority: 1, Name: "a"},
+			"Default": {Priority: 0, Name: "Default"},
+			"last":    {Priority: 5, Name: "last"},
+		},
+	}
+
+	want := []string{"Default", "a", "b", "last"}
+	for run := 0; run < 10; run++ {
+		groups := config.SortedGroups()
+		if len(groups) != len(want) {
+			t.Fatalf("SortedGroups() returned %d groups, want %d", len(groups), len(want))
+		}
+		for i, group := range groups {
+			if group.Name != want[i] {
+				t.Errorf("SortedGroups()[%d] = %q, want %q", i, group.Name, want[i])
+			}
+		}
+	}
+}
+
+func TestConfiguration_SortedArguments(t *testing.T) {
+	config := Configuration{
+		Groups: map[string]*Group{
+			"Default": {
+				Name: "Default",
+				Arguments: []*Argument{
+					{Position: 2, Name: "second"},
+					{Position: 1, Name: "first"},
+				},
+			},
+			"Other": {
+				Priority:  1,
+				Name:      "Other",
+				Arguments: []*Argument{{Position: 3, Name: "third"}},
+			},
+		},
+	}
+
+	want := []string{"first", "second", "third"}
+	arguments := config.SortedArguments()
+	if len(arguments) != len(want) {
+		t.Fatalf("SortedArguments() returned %d arguments, want %d", len(arguments), len(want))
+	}
+	for i, argument := range arguments {
+		if argument.Name != want[i] {
+			t.Errorf("SortedArguments()[%d] = %q, want %q", i, argument.Name, want[i])
+		}
+	}
+}
+
+func TestConfiguration_SectionsAt(t *testing.T) {
+	config := Configuration{
+		Sections: []*Section{
+			{Title: "Environment", Position: SectionBeforeOptions},
+			{Title: "See also", Position: SectionBottom},
+			{Title: "Copyright", Position: SectionBottom},
+		},
+	}
+
+	bottom := config.SectionsAt(SectionBottom)
+	if len(bottom) != 2 || bottom[0].Title != "See also" || bottom[1].Title != "Copyright" {
+		t.Errorf("SectionsAt(SectionBottom) = %v, want See also then Copyright", bottom)
+	}
+	if top := config.SectionsAt(SectionTop); len(top) != 0 {
+		t.Errorf("SectionsAt(SectionTop) returned %d sections, want 0", len(top))
+	}
+}
+
+func TestConfiguration_FindOption(t *testing.T) {
+	option := &Option{Short: "t", Long: "timeout", Aliases: []string{"T", "time-limit"}, RenamedFrom: []string{"wait"}}
+	config := Configuration{
+		Groups: map[string]*Group{
+			"Request": {Name: "Request", Options: []*Option{option}},
+		},
+	}
+
+	for _, name := range []string{"t", "timeout", "T", "time-limit", "wait"} {
+		got, group := config.FindOption(name)
+		if got != option || group == nil || group.Name != "Request" {
+			t.Errorf("FindOption(%q) = %v, %v; want the timeout option", name, got, group)
+		}
+	}
+	if got, _ := config.FindOption("missing"); got != nil {
+		t.Errorf("FindOption(%q) = %v, want nil", "missing", got)
+	}
+	if got, _ := config.FindOption(""); got != nil {
+		t.Errorf("FindOption(%q) = %v, want nil", "", got)
+	}
+}
+
+func TestConfiguration_Options(t *testing.T) {
+	verbose := &Option{Long: "verbose"}
+	output := &Option{Long: "output"}
+	timeout := &Option{Long: "timeout"}
+	config := Configuration{
+		Groups: map[string]*Group{
+			"Request": {Priority: 1, Name: "Request", Options: []*Option{timeout}},
+			"Default": {Priority: 0, Name: "Default", Options: []*Option{verbose, output}},
+		},
+	}
+
+	want := []*Option{verbose, output, timeout}
+	got := config.Options()
+	if len(got) != len(want) {
+		t.Fatalf("Options() returned %d options, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Options()[%d] = %q, want %q", i, got[i].Long, want[i].Long)
+		}
+	}
+}
+
+func TestConfiguration_DeprecationWarning(t *testing.T) {
+	config := Configuration{
+		Groups: map[string]*Group{
+			"Default": {
+				Name: "Default",
+				Options: []*Option{
+					{Long: "current"},
+					{Long: "old", Deprecated: "renamed", Replacement: "current"},
+					{Long: "output", Aliases: []string{"out-file"}, RenamedFrom: []string{"out"}},
+				},
+			},
+			"Legacy": {
+				Name:       "Legacy",
+				Deprecated: "legacy options will be removed",
+				Options:    []*Option{{Long: "compat"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		wantWarning string
+		wantOK      bool
+	}{
+		{"current", "", false},
+		{"missing", "", false},
+		{"old", "option --old is deprecated: renamed, use --current instead", true},
+		{"compat", "option --compat is deprecated: legacy options will be removed", true},
+		{"out-file", "", false},
+		{"out", "option --out is deprecated, use --output instead", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, ok := config.DeprecationWarning(tt.name)
+			if warning != tt.wantWarning || ok != tt.wantOK {
+				t.Errorf("DeprecationWarning(%q) = %q, %v; want %q, %v", tt.name, warning, ok, tt.wantWarning, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorKind classifies a UsageError so that formatters and tooling can react
+// to the type of problem without parsing the error message.
+type ErrorKind string
+
+const (
+	ErrorKindUnknownOption   ErrorKind = "unknown_option"   // An option that was not registered was provided
+	ErrorKindInvalidValue    ErrorKind = "invalid_value"    // An option value could not be converted to its type
+	ErrorKindMissingValue    ErrorKind = "missing_value"    // An option that requires a value was provided without one
+	ErrorKindAmbiguousOption ErrorKind = "ambiguous_option" // An abbreviated option matches several options
+	ErrorKindMissingOption   ErrorKind = "missing_option"   // A required option was not provided
+	ErrorKindMissingArgument ErrorKind = "missing_argument" // A required positional argument was not provided
+	ErrorKindResponseFile    ErrorKind = "response_file"    // A response file could not be read or parsed
+	ErrorKindConfigFile      ErrorKind = "config_file"      // A configuration file could not be read or parsed
+	ErrorKindAlias           ErrorKind = "alias"            // A command alias could not be expanded
+	ErrorKindSyntax          ErrorKind = "syntax"           // The command line could not be tokenized
+	ErrorKindGeneric         ErrorKind = "error"            // Any other error
+)
+
+// ErrorMode selects how the text formatters present parse and validation errors.
+type ErrorMode string
+
+const (
+	ErrorModeConcise ErrorMode = "concise" // The error, suggestions, the synopsis and a hint to run --help
+	ErrorModeFull    ErrorMode = "full"    // The error together with the complete usage information
+)
+
+// UsageError describes a problem with the command line in a structured form.
+// Formatters use the fields to render errors, for example the JSON formatter
+// emits each field so that wrapper tools can consume errors programmatically.
+type UsageError struct {
+	Kind        ErrorKind // Classification of the error
+	Option      string    // Name of the offending option without dashes, if any
+	Argument    string    // Name or value of the offending positional argument, if any
+	Message     string    // Human readable description of the error
+	Suggestions []string  // Possible corrections for the user, if any
+	Source      string    // "file:line" of the offending argument if it was read from a response file
+	Args        []string  // Command line the error was found in, with the values of secret options masked
+	Err         error     // Underlying error, if any
+}
+
+// Error returns the human readable message of the error prefixed by its source
+// and followed by any suggestions, e.g. "args.rsp:3: ... (did you mean --timeout?)".
+func (e *UsageError) Error() string {
+	if hint := e.SuggestionHint(); hint != "" {
+		return fmt.Sprintf("%s (%s)", e.Summary(), hint)
+	}
+	return e.Summary()
+}
+
+// Summary returns the message of the error prefixed by its source, without
+// the suggestions.
+func (e *UsageError) Summary() string {
+	if e.Source != "" {
+		return e.Source + ": " + e.Message
+	}
+	return e.Message
+}
+
+// SuggestionHint returns the suggestions as a question such as "did you mean
+// --timeout?", or an empty string if there are none.
+func (e *UsageError) SuggestionHint() string {
+	switch len(e.Suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("did you mean %s?", e.Suggestions[0])
+	}
+	return fmt.Sprintf("did you mean one of %s?", strings.Join(e.Suggestions, ", "))
+}
+
+// Unwrap returns the underlying error so errors.Is and errors.As can inspect it.
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// flagErrorPrefixes maps the messages produced by the standard flag package to
+// the matching error kind. The option name follows the prefix up to the next
+// colon or the end of the message.
+var flagErrorPrefixes = []struct {
+	prefix string
+	kind   ErrorKind
+}{
+	{"flag provided but not defined: -", ErrorKindUnknownOption},
+	{"flag needs an argument: -", ErrorKindMissingValue},
+	{"bad flag syntax: ", ErrorKindSyntax},
+}
+
+// NewUsageError converts err into a UsageError. Errors produced by the standard
+// flag package are classified by kind and the offending option is extracted.
+// If err already is (or wraps) a UsageError, that error is returned unchanged,
+// and if err is nil, nil is returned.
+func NewUsageError(err error) *UsageError {
+	if err == nil {
+		return nil
+	}
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		return usageErr
+	}
+
+	message := err.Error()
+	result := &UsageError{Kind: ErrorKindGeneric, Message: message, Err: err}
+
+	for _, p := range flagErrorPrefixes {
+		if strings.HasPrefix(message, p.prefix) {
+			result.Kind = p.kind
+			if p.kind != ErrorKindSyntax {
+				result.Option = strings.TrimLeft(strings.TrimPrefix(message, p.prefix), "-")
+			}
+			return result
+		}
+	}
+
+	// invalid value "x" for flag -name: reason
+	// invalid boolean value "x" for -name: reason
+	if strings.HasPrefix(message, "invalid ") {
+		if i := strings.Index(message, " for "); i >= 0 {
+			rest := strings.TrimPrefix(message[i+len(" for "):], "flag ")
+			if j := strings.Index(rest, ":"); j >= 0 {
+				rest = rest[:j]
+			}
+			result.Kind = ErrorKindInvalidValue
+			result.Option = strings.TrimLeft(rest, "-")
+		}
+	}
+	return result
+}
+
+// MaskValue replaces the offending value in the message of an invalid value
+// error with SecretMask, so that the value of a secret option is not shown.
+// The value is expected in double quotes before " for ", as in the messages
+// of the standard flag package.
+func (e *UsageError) MaskValue() {
+	if e.Kind != ErrorKindInvalidValue {
+		return
+	}
+	start := strings.Index(e.Message, `"`)
+	end := strings.LastIndex(e.Message, `" for `)
+	if start < 0 || end <= start {
+		return
+	}
+	e.Message = e.Message[:start+1] + SecretMask + e.Message[end:]
+}
+
+// SuggestOptions fills the suggestions of an unknown option error with the
+// registered option names closest to the offending option, using at most
+// maxDistance edits. Errors of other kinds are left unchanged.
+func (e *UsageError) SuggestOptions(config *Configuration, maxDistance int) {
+	if e.Kind != ErrorKindUnknownOption {
+		return
+	}
+	for _, name := range Suggest(e.Option, config.OptionNames(), maxDistance) {
+		e.Suggestions = append(e.Suggestions, Dashed(name))
+	}
+}
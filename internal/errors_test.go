@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestNewUsageError(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantKind   ErrorKind
+		wantOption string
+	}{
+		{"unknown option", []string{"--timout", "5"}, ErrorKindUnknownOption, "timout"},
+		{"invalid value", []string{"--timeout", "abc"}, ErrorKindInvalidValue, "timeout"},
+		{"invalid boolean", []string{"--verbose=maybe"}, ErrorKindInvalidValue, "verbose"},
+		{"missing value", []string{"--timeout"}, ErrorKindMissingValue, "timeout"},
+		{"bad syntax", []string{"---timeout"}, ErrorKindSyntax, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Int("timeout", 10, "")
+			fs.Bool("verbose", false, "")
+
+			err := fs.Parse(tt.args)
+			if err == nil {
+				t.Fatal("Parse() returned no error")
+			}
+
+			usageErr := NewUsageError(err)
+			if usageErr.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", usageErr.Kind, tt.wantKind)
+			}
+			if usageErr.Option != tt.wantOption {
+				t.Errorf("Option = %q, want %q", usageErr.Option, tt.wantOption)
+			}
+			if !errors.Is(usageErr, err) {
+				t.Error("UsageError does not wrap the original error")
+			}
+		})
+	}
+}
+
+func TestNewUsageError_Existing(t *testing.T) {
+	original := &UsageError{Kind: ErrorKindInvalidValue, Message: "bad"}
+	if got := NewUsageError(original); got != original {
+		t.Errorf("NewUsageError() = %v, want the original error", got)
+	}
+}
+
+func TestNewUsageError_Nil(t *testing.T) {
+	if got := NewUsageError(nil); got != nil {
+		t.Errorf("NewUsageError(nil) = %v, want nil", got)
+	}
+	if got := UsageErrors(nil); len(got) != 0 {
+		t.Errorf("UsageErrors(nil) = %v, want none", got)
+	}
+}
+
+func TestNewUsageError_Generic(t *testing.T) {
+	got := NewUsageError(errors.New("something failed"))
+	if got.Kind != ErrorKindGeneric {
+		t.Errorf("Kind = %q, want %q", got.Kind, ErrorKindGeneric)
+	}
+	if got.Error() != "something failed" {
+		t.Errorf("Error() = %q, want %q", got.Error(), "something failed")
+	}
+}
+
+func TestUsageError_Error(t *testing.T) {
+	tests := []struct {
+		name        string
+		suggestions []string
+		want        string
+	}{
+		{"no suggestions", nil, "unknown option"},
+		{"one suggestion", []string{"--timeout"}, "unknown option (did you mean --timeout?)"},
+		{"several suggestions", []string{"--verbose", "--version"}, "unknown option (did you mean one of --verbose, --version?)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &UsageError{Message: "unknown option", Suggestions: tt.suggestions}
+			if got := err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageError_SummaryAndHint(t *testing.T) {
+	err := &UsageError{Message: "unknown option", Source: "args.rsp:3", Suggestions: []string{"--timeout"}}
+	if got, want := err.Summary(), "args.rsp:3: unknown option"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if got, want := err.SuggestionHint(), "did you mean --timeout?"; got != want {
+		t.Errorf("SuggestionHint() = %q, want %q", got, want)
+	}
+	err.Suggestions = nil
+	if got := err.SuggestionHint(); got != "" {
+		t.Errorf("SuggestionHint() = %q, want empty without suggestions", got)
+	}
+}
+
+func TestUsageError_SuggestOptions(t *testing.T) {
+	config := &Configuration{
+		Groups: map[string]*Group{
+			"Default": {Name: "Default", Options: []*Option{{Short: "t", Long: "timeout"}, {Short: "v", Long: "verbose"}}},
+		},
+	}
+
+	unknown := &UsageError{Kind: ErrorKindUnknownOption, Option: "timout"}
+	unknown.SuggestOptions(config, 2)
+	if len(unknown.Suggestions) != 1 || unknown.Suggestions[0] != "--timeout" {
+		t.Errorf("Suggestions = %q, want [--timeout]", unknown.Suggestions)
+	}
+
+	invalid := &UsageError{Kind: ErrorKindInvalidValue, Option: "timout"}
+	invalid.SuggestOptions(config, 2)
+	if len(invalid.Suggestions) != 0 {
+		t.Errorf("Suggestions = %q, want none for invalid value errors", invalid.Suggestions)
+	}
+}
+
+func TestUsageError_MaskValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *UsageError
+		message string
+	}{
+		{
+			name:    "flag value",
+			err:     &UsageError{Kind: ErrorKindInvalidValue, Message: `invalid value "hunter2" for flag -pin: parse error`},
+			message: `invalid value "` + SecretMask + `" for flag -pin: parse error`,
+		},
+		{
+			name:    "boolean value",
+			err:     &UsageError{Kind: ErrorKindInvalidValue, Message: `invalid boolean value "x" for -secret: parse error`},
+			message: `invalid boolean value "` + SecretMask + `" for -secret: parse error`,
+		},
+		{
+			name:    "other kinds are unchanged",
+			err:     &UsageError{Kind: ErrorKindMissingValue, Message: `flag needs an argument: -pin`},
+			message: `flag needs an argument: -pin`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.err.MaskValue()
+			if tt.err.Message != tt.message {
+				t.Errorf("Message = %q, want %q", tt.err.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldTag holds the settings of a struct field parsed from its `usage` tag.
+//
+// The tag is a comma separated list of key=value pairs and flags, e.g.
+// `usage:"short=t,long=timeout,desc=Request timeout,env=TIMEOUT,required"`.
+// Values containing commas can be enclosed in single quotes, e.g.
+// `usage:"desc='Hosts to query, in order'"`. Lists such as choices, aliases
+// and former names are separated by "|", e.g. `usage:"aliases=o|out,renamed=dest"`.
+// The tag "-" skips the field.
+type FieldTag struct {
+	Skip        bool     // The field is not bound to an option
+	Short       string   // Single-character option name
+	Long        string   // Long option name
+	Description string   // Help text, or the group description for nested structs
+	Extra       string   // Additional information shown in usage output
+	Group       string   // Name of the group the option, or nested struct, belongs to
+	Env         string   // Environment variable providing a value when the option is not given
+	RenamedEnv  []string // Former environment variables, given after the current one separated by "|"
+	Default     string   // Default value, replacing the current value of the field
+	HasDefault  bool     // A default value was given, possibly empty
+	Required    bool     // The option must be provided
+	Hidden      bool     // The option is not shown in usage output
+	Advanced    bool     // The option is only shown with --help-all
+	Deprecated  string   // Deprecation message
+	Choices     []string // Allowed values, separated by "|" in the tag
+	Secret      bool     // The value is sensitive
+	Aliases     []string // Additional option names, separated by "|" in the tag
+	RenamedFrom []string // Former option names, separated by "|" in the tag
+}
+
+// ParseFieldTag parses the value of a `usage` struct tag. Unknown keys and
+// unterminated quotes are reported as errors.
+func ParseFieldTag(tag string) (*FieldTag, error) {
+	result := &FieldTag{}
+	if strings.TrimSpace(tag) == "-" {
+		result.Skip = true
+		return result, nil
+	}
+
+	entries, err := splitTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		key, value, hasValue := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		switch key {
+		case "short":
+			result.Short = value
+		case "long":
+			result.Long = value
+		case "desc", "description", "help":
+			result.Description = value
+		case "extra":
+			result.Extra = value
+		case "group":
+			result.Group = value
+		case "env":
+			names := strings.Split(value, "|")
+			result.Env = names[0]
+			if len(names) > 1 {
+				result.RenamedEnv = names[1:]
+			}
+		case "default":
+			result.Default = value
+			result.HasDefault = true
+		case "choices":
+			result.Choices = strings.Split(value, "|")
+		case "alias", "aliases":
+			result.Aliases = strings.Split(value, "|")
+		case "renamed":
+			result.RenamedFrom = strings.Split(value, "|")
+		case "deprecated":
+			result.Deprecated = value
+			if !hasValue || value == "" {
+				result.Deprecated = "it will be removed in a future release"
+			}
+		case "required", "hidden", "advanced", "secret":
+			if hasValue {
+				return nil, fmt.Errorf("tag flag %q does not take a value", key)
+			}
+			switch key {
+			case "required":
+				result.Required = true
+			case "hidden":
+				result.Hidden = true
+			case "advanced":
+				result.Advanced = true
+			case "secret":
+				result.Secret = true
+			}
+		default:
+			return nil, fmt.Errorf("unknown tag key %q", key)
+		}
+	}
+	return result, nil
+}
+
+// splitTag splits a tag at the commas that are not enclosed in single quotes
+// and removes the quotes. Empty entries are dropped.
+func splitTag(tag string) ([]string, error) {
+	var entries []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range tag {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			if current.Len() > 0 {
+				entries = append(entries, current.String())
+			}
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in tag %q", tag)
+	}
+	if current.Len() > 0 {
+		entries = append(entries, current.String())
+	}
+	return entries, nil
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFieldTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    *FieldTag
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			tag:  "",
+			want: &FieldTag{},
+		},
+		{
+			name: "skip",
+			tag:  "-",
+			want: &FieldTag{Skip: true},
+		},
+		{
+			name: "all keys",
+			tag:  "short=t,long=timeout,desc=Request timeout,extra=seconds,group=Request,env=TIMEOUT,default=30s,required,hidden,advanced",
+			want: &FieldTag{
+				Short:       "t",
+				Long:        "timeout",
+				Description: "Request timeout",
+				Extra:       "seconds",
+				Group:       "Request",
+				Env:         "TIMEOUT",
+				Default:     "30s",
+				HasDefault:  true,
+				Required:    true,
+				Hidden:      true,
+				Advanced:    true,
+			},
+		},
+		{
+			name: "quoted value with commas",
+			tag:  "desc='Hosts to query, in order', long=hosts",
+			want: &FieldTag{Long: "hosts", Description: "Hosts to query, in order"},
+		},
+		{
+			name: "choices and secret",
+			tag:  "choices=json|text,secret",
+			want: &FieldTag{Choices: []string{"json", "text"}, Secret: true},
+		},
+		{
+			name: "aliases and former names",
+			tag:  "long=output,aliases=o|out-file,renamed=dest,env=OUTPUT|DEST",
+			want: &FieldTag{
+				Long:        "output",
+				Aliases:     []string{"o", "out-file"},
+				RenamedFrom: []string{"dest"},
+				Env:         "OUTPUT",
+				RenamedEnv:  []string{"DEST"},
+			},
+		},
+		{
+			name: "empty default",
+			tag:  "default=",
+			want: &FieldTag{HasDefault: true},
+		},
+		{
+			name: "deprecated without message",
+			tag:  "deprecated",
+			want: &FieldTag{Deprecated: "it will be removed in a future release"},
+		},
+		{
+			name:    "unknown key",
+			tag:     "shrt=t",
+			wantErr: true,
+		},
+		{
+			name:    "flag with value",
+			tag:     "required=false",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quote",
+			tag:     "desc='oops",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFieldTag(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFieldTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFieldTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
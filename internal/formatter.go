@@ -2,6 +2,12 @@
 // These types are not intended for direct use by library consumers.
 package internal
 
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
 // Formatter defines the interface for formatting and displaying usage information.
 // Implementations can provide different output styles (plain text, colored, etc.).
 type Formatter interface {
@@ -13,3 +19,79 @@ type Formatter interface {
 	// to the configured error writer.
 	PrintError(err error)
 }
+
+// WarningPrinter is implemented by formatters that print warnings, such as the
+// use of a deprecated option. Warnings for formatters that do not implement it
+// are written to os.Stderr.
+type WarningPrinter interface {
+	// PrintWarning outputs a warning message to the configured error writer
+	// without aborting.
+	PrintWarning(message string)
+}
+
+// Writers holds the destinations of the output of a ModelFormatter.
+type Writers struct {
+	Output io.Writer // Writer for normal usage output
+	Error  io.Writer // Writer for errors and warnings
+}
+
+// ModelFormatter renders usage information, errors and warnings from a Model.
+// Unlike Formatter it does not depend on the internal Configuration, so it can
+// be implemented outside of this module. Errors returned by the methods are
+// reported on the error writer.
+type ModelFormatter interface {
+	// FormatUsage writes the usage information described by model.
+	FormatUsage(w Writers, model *Model) error
+
+	// FormatError writes a parse or validation error.
+	FormatError(w Writers, model *Model, err error) error
+
+	// FormatWarning writes a warning, such as the use of a deprecated option,
+	// without aborting.
+	FormatWarning(w Writers, model *Model, message string) error
+}
+
+// ModelFormatterAdapter implements Formatter by building a Model from the
+// Configuration and passing it to a ModelFormatter.
+type ModelFormatterAdapter struct {
+	Formatter     ModelFormatter // Formatter rendering the model
+	Output        io.Writer      // Writer for normal usage output (defaults to os.Stdout)
+	Error         io.Writer      // Writer for error messages (defaults to os.Stderr)
+	Configuration *Configuration // Application and option configuration
+}
+
+// PrintUsage passes the current model to FormatUsage.
+func (a *ModelFormatterAdapter) PrintUsage() {
+	w := a.writers()
+	a.report(w, a.Formatter.FormatUsage(w, NewModel(a.Configuration)))
+}
+
+// PrintError passes the current model and err to FormatError.
+func (a *ModelFormatterAdapter) PrintError(err error) {
+	w := a.writers()
+	a.report(w, a.Formatter.FormatError(w, NewModel(a.Configuration), err))
+}
+
+// PrintWarning passes the current model and message to FormatWarning.
+func (a *ModelFormatterAdapter) PrintWarning(message string) {
+	w := a.writers()
+	a.report(w, a.Formatter.FormatWarning(w, NewModel(a.Configuration), message))
+}
+
+// writers returns the configured writers with the defaults applied.
+func (a *ModelFormatterAdapter) writers() Writers {
+	if a.Output == nil {
+		a.Output = os.Stdout
+	}
+	if a.Error == nil {
+		a.Error = os.Stderr
+	}
+	return Writers{Output: a.Output, Error: a.Error}
+}
+
+// report writes an error returned by the formatter to the error writer.
+func (a *ModelFormatterAdapter) report(w Writers, err error) {
+	if err != nil {
+		fmt.Fprintf(w.Error, "formatter error: %v\n", err)
+	}
+}
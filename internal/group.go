@@ -11,6 +11,26 @@ type Group struct {
 	Description string
 	Options     []*Option
 	Arguments   []*Argument
+	Hidden      bool   // Group is never shown in usage output, its options are still parsed
+	Advanced    bool   // Group is only shown when all options are requested (--help-all)
+	Deprecated  string // Deprecation message applied to every option in the group
+}
+
+// VisibleOptions returns the options of the group that should be shown in usage
+// output. Hidden options are never shown. Advanced and deprecated options, and
+// all options of an advanced or deprecated group, are only shown when showAll is true.
+func (g *Group) VisibleOptions(showAll bool) []*Option {
+	if g.Hidden || (!showAll && (g.Advanced || g.Deprecated != "")) {
+		return nil
+	}
+	var options []*Option
+	for _, option := range g.Options {
+		if option.Hidden || (!showAll && (option.Advanced || option.IsDeprecated())) {
+			continue
+		}
+		options = append(options, option)
+	}
+	return options
 }
 
 // AddOption adds an option to this group.
@@ -47,7 +67,13 @@ func (g *Group) RemoveArgument(argument *Argument) {
 // display for proper alignment. Returns the widths for short names, long names,
 // default values, and descriptions respectively.
 func (g *Group) CalculateOptionWidths() (shortWidth, longWidth, defaultValueWidth, descriptionWidth int) {
-	for _, option := range g.Options {
+	return calculateOptionWidths(g.Options)
+}
+
+// calculateOptionWidths calculates the column widths for the given options.
+// See Group.CalculateOptionWidths.
+func calculateOptionWidths(options []*Option) (shortWidth, longWidth, defaultValueWidth, descriptionWidth int) {
+	for _, option := range options {
 		if len(option.Short) > shortWidth {
 			shortWidth = len(option.Short)
 		}
@@ -58,8 +84,8 @@ func (g *Group) CalculateOptionWidths() (shortWidth, longWidth, defaultValueWidt
 		if len(defaultValueStr) > defaultValueWidth {
 			defaultValueWidth = len(defaultValueStr)
 		}
-		if len(option.Description) > descriptionWidth {
-			descriptionWidth = len(option.Description)
+		if len(option.HelpDescription()) > descriptionWidth {
+			descriptionWidth = len(option.HelpDescription())
 		}
 	}
 	return
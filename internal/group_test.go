@@ -293,3 +293,62 @@ func TestGroup_CalculateOptionWidths(t *testing.T) {
 		})
 	}
 }
+
+func TestGroup_VisibleOptions(t *testing.T) {
+	regular := &Option{Long: "regular"}
+	hidden := &Option{Long: "hidden", Hidden: true}
+	advanced := &Option{Long: "advanced", Advanced: true}
+	deprecated := &Option{Long: "deprecated", Deprecated: "old"}
+
+	tests := []struct {
+		name    string
+		group   *Group
+		showAll bool
+		want    []*Option
+	}{
+		{
+			name:    "regular help",
+			group:   &Group{Options: []*Option{regular, hidden, advanced, deprecated}},
+			showAll: false,
+			want:    []*Option{regular},
+		},
+		{
+			name:    "all help",
+			group:   &Group{Options: []*Option{regular, hidden, advanced, deprecated}},
+			showAll: true,
+			want:    []*Option{regular, advanced, deprecated},
+		},
+		{
+			name:    "hidden group",
+			group:   &Group{Hidden: true, Options: []*Option{regular}},
+			showAll: true,
+			want:    nil,
+		},
+		{
+			name:    "advanced group in regular help",
+			group:   &Group{Advanced: true, Options: []*Option{regular}},
+			showAll: false,
+			want:    nil,
+		},
+		{
+			name:    "advanced group in all help",
+			group:   &Group{Advanced: true, Options: []*Option{regular}},
+			showAll: true,
+			want:    []*Option{regular},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.group.VisibleOptions(tt.showAll)
+			if len(got) != len(tt.want) {
+				t.Fatalf("VisibleOptions() returned %d options, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("VisibleOptions()[%d] = %q, want %q", i, got[i].Long, tt.want[i].Long)
+				}
+			}
+		})
+	}
+}
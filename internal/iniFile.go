@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IniEntry is a setting read from an INI file.
+type IniEntry struct {
+	Section    string // Name of the section, lower-cased, empty before the first section header
+	Subsection string // Name after the section name, e.g. "dev" for [profile "dev"]
+	Key        string // Name of the setting
+	Value      string // Value with surrounding whitespace removed, quotes are kept
+	Source     string // "file:line" the setting was read from
+}
+
+// ParseIni parses the contents of an INI file read from path. Sections are
+// introduced by "[name]" or `[name "subsection"]` headers, where the quotes
+// may be left out for subsections without spaces, settings are written as
+// "key = value" and lines starting with "#" or ";" are comments. Values are
+// kept as written, so they may use shell-like quoting.
+func ParseIni(data string, path string) ([]IniEntry, error) {
+	var entries []IniEntry
+	section, subsection := "", ""
+	for i, line := range strings.Split(data, "\n") {
+		source := fmt.Sprintf("%s:%d", path, i+1)
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s: unterminated section header", source)
+			}
+			header := strings.TrimSpace(line[1 : len(line)-1])
+			name, quoted, _ := strings.Cut(header, " ")
+			section, subsection = strings.ToLower(name), strings.TrimSpace(quoted)
+			if strings.HasPrefix(subsection, `"`) {
+				unquoted, err := strconv.Unquote(subsection)
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid subsection %s", source, subsection)
+				}
+				subsection = unquoted
+			}
+			if section == "" {
+				return nil, fmt.Errorf("%s: empty section name", source)
+			}
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				return nil, fmt.Errorf("%s: expected key = value", source)
+			}
+			entries = append(entries, IniEntry{
+				Section:    section,
+				Subsection: subsection,
+				Key:        key,
+				Value:      strings.TrimSpace(value),
+				Source:     source,
+			})
+		}
+	}
+	return entries, nil
+}
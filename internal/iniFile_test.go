@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIni(t *testing.T) {
+	data := `# global settings
+verbose = true
+
+[Alias]
+co = checkout --force
+msg = commit -m "work in progress"
+; comment
+
+[profile "prod east"]
+region=us-east-1
+empty =
+
+[profile dev]
+region = eu-west-1
+`
+	want := []IniEntry{
+		{Key: "verbose", Value: "true", Source: "app.ini:2"},
+		{Section: "alias", Key: "co", Value: "checkout --force", Source: "app.ini:5"},
+		{Section: "alias", Key: "msg", Value: `commit -m "work in progress"`, Source: "app.ini:6"},
+		{Section: "profile", Subsection: "prod east", Key: "region", Value: "us-east-1", Source: "app.ini:10"},
+		{Section: "profile", Subsection: "prod east", Key: "empty", Value: "", Source: "app.ini:11"},
+		{Section: "profile", Subsection: "dev", Key: "region", Value: "eu-west-1", Source: "app.ini:14"},
+	}
+	got, err := ParseIni(data, "app.ini")
+	if err != nil {
+		t.Fatalf("ParseIni() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseIni() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseIni_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unterminated header", "[alias", "app.ini:1: unterminated section header"},
+		{"empty section", "\n[ ]", "app.ini:2: empty section name"},
+		{"invalid subsection", `[profile "dev]`, `app.ini:1: invalid subsection "dev`},
+		{"missing value", "[alias]\nco", "app.ini:2: expected key = value"},
+		{"missing key", "= value", "app.ini:1: expected key = value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseIni(tt.data, "app.ini")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseIni() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
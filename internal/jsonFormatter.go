@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// JSONFormatter implements the Formatter interface by emitting the usage
+// structure and errors as JSON documents. It is intended for IDE plugins and
+// wrapper tooling that consume help and parse errors programmatically.
+type JSONFormatter struct {
+	Output        io.Writer      // Writer for normal usage output (defaults to os.Stdout)
+	Error         io.Writer      // Writer for error messages (defaults to os.Stderr)
+	Configuration *Configuration // Application and option configuration
+}
+
+// jsonUsage is the document written by JSONFormatter.PrintUsage.
+type jsonUsage struct {
+	Name        string         `json:"name"`
+	Version     string         `json:"version,omitempty"`
+	BuildDate   string         `json:"build_date,omitempty"`
+	CommitHash  string         `json:"commit_hash,omitempty"`
+	Branch      string         `json:"branch,omitempty"`
+	Modified    bool           `json:"modified,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Groups      []jsonGroup    `json:"groups"`
+	Arguments   []jsonArgument `json:"arguments"`
+	Examples    []jsonExample  `json:"examples"`
+	Sections    []jsonSection  `json:"sections"`
+}
+
+// jsonGroup is the JSON representation of a Group.
+type jsonGroup struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Priority    int          `json:"priority"`
+	Options     []jsonOption `json:"options"`
+}
+
+// jsonOption is the JSON representation of an Option.
+type jsonOption struct {
+	Short       string      `json:"short,omitempty"`
+	Long        string      `json:"long,omitempty"`
+	Type        string      `json:"type"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description,omitempty"`
+	Extra       string      `json:"extra,omitempty"`
+	Advanced    bool        `json:"advanced,omitempty"`
+	Deprecated  string      `json:"deprecated,omitempty"`
+	Replacement string      `json:"replacement,omitempty"`
+	Env         string      `json:"env,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Choices     []string    `json:"choices,omitempty"`
+	Secret      bool        `json:"secret,omitempty"`
+	Aliases     []string    `json:"aliases,omitempty"`
+}
+
+// jsonArgument is the JSON representation of an Argument.
+type jsonArgument struct {
+	Position    int    `json:"position"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Extra       string `json:"extra,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// jsonExample is the JSON representation of an Example.
+type jsonExample struct {
+	Command     string `json:"command"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// jsonSection is the JSON representation of a Section.
+type jsonSection struct {
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	Position string `json:"position"`
+}
+
+// jsonWarning is the document written by JSONFormatter.PrintWarning.
+type jsonWarning struct {
+	Warning jsonWarningDetail `json:"warning"`
+}
+
+// jsonWarningDetail is the JSON representation of a warning.
+type jsonWarningDetail struct {
+	Message string `json:"message"`
+}
+
+// jsonError is the document written by JSONFormatter.PrintError. Errors holds
+// every error of an ErrorList, Error the first one.
+type jsonError struct {
+	Error  jsonErrorDetail   `json:"error"`
+	Errors []jsonErrorDetail `json:"errors,omitempty"`
+}
+
+// jsonErrorDetail is the JSON representation of a UsageError.
+type jsonErrorDetail struct {
+	Kind        ErrorKind `json:"kind"`
+	Option      string    `json:"option,omitempty"`
+	Argument    string    `json:"argument,omitempty"`
+	Message     string    `json:"message"`
+	Suggestions []string  `json:"suggestions"`
+	Source      string    `json:"source,omitempty"`
+	Token       string    `json:"token,omitempty"`
+	Index       *int      `json:"index,omitempty"`
+}
+
+// PrintUsage writes the application metadata, option groups in priority order,
+// positional arguments, examples and custom sections as a single JSON document.
+// If Output is nil, it defaults to os.Stdout.
+func (f *JSONFormatter) PrintUsage() {
+	if f.Output == nil {
+		f.Output = os.Stdout
+	}
+
+	doc := jsonUsage{
+		Name:        f.Configuration.ApplicationName,
+		Version:     f.Configuration.ApplicationVersion,
+		BuildDate:   f.Configuration.ApplicationBuildDate,
+		CommitHash:  f.Configuration.ApplicationCommitHash,
+		Branch:      f.Configuration.ApplicationBranch,
+		Modified:    f.Configuration.ApplicationModified,
+		Description: f.Configuration.ApplicationDescription,
+		Groups:      []jsonGroup{},
+		Arguments:   []jsonArgument{},
+		Examples:    []jsonExample{},
+		Sections:    []jsonSection{},
+	}
+
+	for _, group := range f.Configuration.SortedGroups() {
+		options := group.VisibleOptions(f.Configuration.ShowAll)
+		if len(options) == 0 {
+			continue
+		}
+		g := jsonGroup{
+			Name:        group.Name,
+			Description: group.Description,
+			Priority:    group.Priority,
+			Options:     []jsonOption{},
+		}
+		for _, option := range options {
+			deprecated := option.Deprecated
+			if deprecated == "" {
+				deprecated = group.Deprecated
+			}
+			g.Options = append(g.Options, jsonOption{
+				Short:       option.Short,
+				Long:        option.Long,
+				Type:        option.TypeName(),
+				Default:     jsonDefault(option),
+				Description: option.Description,
+				Extra:       option.Extra,
+				Advanced:    option.Advanced || group.Advanced,
+				Deprecated:  deprecated,
+				Replacement: option.Replacement,
+				Env:         option.Env,
+				Required:    option.Required,
+				Choices:     option.Choices,
+				Secret:      option.Secret,
+				Aliases:     option.Aliases,
+			})
+		}
+		doc.Groups = append(doc.Groups, g)
+	}
+
+	for _, argument := range f.Configuration.SortedArguments() {
+		doc.Arguments = append(doc.Arguments, jsonArgument{
+			Position:    argument.Position,
+			Name:        argument.Name,
+			Description: argument.Description,
+			Extra:       argument.Extra,
+			Required:    argument.Required,
+		})
+	}
+
+	for _, example := range f.Configuration.Examples {
+		doc.Examples = append(doc.Examples, jsonExample{
+			Command:     example.Command,
+			Explanation: example.Explanation,
+		})
+	}
+
+	for _, section := range f.Configuration.Sections {
+		doc.Sections = append(doc.Sections, jsonSection{
+			Title:    section.Title,
+			Body:     section.Body,
+			Position: section.Position.String(),
+		})
+	}
+
+	f.encode(f.Output, doc)
+}
+
+// PrintError writes the error as a JSON document containing its kind, the
+// offending option or argument, the message, any suggestions and the location
+// of the offending token on the command line. The errors of an ErrorList are
+// additionally listed under "errors".
+// If Error is nil, it defaults to os.Stderr. Unlike the text formatters the
+// usage information is not repeated, consumers can request it separately.
+// Nothing is written if err is nil or an empty ErrorList.
+func (f *JSONFormatter) PrintError(err error) {
+	if f.Error == nil {
+		f.Error = os.Stderr
+	}
+
+	usageErrs := UsageErrors(err)
+	if len(usageErrs) == 0 {
+		return
+	}
+	var details []jsonErrorDetail
+	for _, usageErr := range usageErrs {
+		details = append(details, jsonErrorDetailOf(usageErr))
+	}
+	doc := jsonError{Error: details[0]}
+	if len(details) > 1 {
+		doc.Errors = details
+	}
+	f.encode(f.Error, doc)
+}
+
+// jsonErrorDetailOf returns the JSON representation of usageErr.
+func jsonErrorDetailOf(usageErr *UsageError) jsonErrorDetail {
+	suggestions := usageErr.Suggestions
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+	detail := jsonErrorDetail{
+		Kind:        usageErr.Kind,
+		Option:      usageErr.Option,
+		Argument:    usageErr.Argument,
+		Message:     usageErr.Message,
+		Suggestions: suggestions,
+		Source:      usageErr.Source,
+	}
+	if location := usageErr.Location(); location.Index >= 0 {
+		index := location.Index
+		detail.Token = location.Token
+		detail.Index = &index
+	}
+	return detail
+}
+
+// PrintWarning writes the warning as a JSON document to the error writer.
+// If Error is nil, it defaults to os.Stderr.
+func (f *JSONFormatter) PrintWarning(message string) {
+	if f.Error == nil {
+		f.Error = os.Stderr
+	}
+
+	f.encode(f.Error, jsonWarning{Warning: jsonWarningDetail{Message: message}})
+}
+
+// encode writes v to w as indented JSON followed by a newline.
+func (f *JSONFormatter) encode(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+// jsonDefault returns the default value of an option for the JSON output, with
+// the defaults of secret options masked.
+func jsonDefault(option *Option) interface{} {
+	if option.Secret && option.Default != nil && fmt.Sprint(option.Default) != "" {
+		return SecretMask
+	}
+	return option.Default
+}
@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestJSONFormatter_PrintUsage(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := &JSONFormatter{
+		Output: &buf,
+		Configuration: &Configuration{
+			ApplicationName:    "myapp",
+			ApplicationVersion: "1.0.0",
+			Groups: map[string]*Group{
+				"Request": {
+					Priority:    1,
+					Name:        "Request",
+					Description: "Request Options",
+					Options: []*Option{
+						{Short: "t", Long: "timeout", Default: 10, Description: "Timeout in seconds"},
+						{Long: "token", Default: "hunter2", Description: "API token", Secret: true},
+					},
+				},
+				"Default": {
+					Name:        "Default",
+					Description: "Default Options",
+					Options: []*Option{
+						{Short: "v", Long: "verbose", Default: false, Description: "Verbose output"},
+					},
+					Arguments: []*Argument{
+						{Position: 1, Name: "url", Description: "The url"},
+					},
+				},
+			},
+			Examples: []*Example{{Command: "myapp https://example.com", Explanation: "Fetch a page"}},
+			Sections: []*Section{{Title: "Bugs", Body: "https://example.com/bugs", Position: SectionBottom}},
+		},
+	}
+
+	formatter.PrintUsage()
+
+	var doc jsonUsage
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("PrintUsage() wrote invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Name != "myapp" || doc.Version != "1.0.0" {
+		t.Errorf("metadata = %q %q, want %q %q", doc.Name, doc.Version, "myapp", "1.0.0")
+	}
+	if len(doc.Groups) != 2 || doc.Groups[0].Name != "Default" || doc.Groups[1].Name != "Request" {
+		t.Fatalf("groups = %+v, want Default then Request", doc.Groups)
+	}
+	option := doc.Groups[1].Options[0]
+	if option.Long != "timeout" || option.Type != "int" || option.Default != float64(10) {
+		t.Errorf("option = %+v, want timeout int 10", option)
+	}
+	if secret := doc.Groups[1].Options[1]; secret.Default != SecretMask || !secret.Secret {
+		t.Errorf("secret option = %+v, want masked default", secret)
+	}
+	if len(doc.Arguments) != 1 || doc.Arguments[0].Name != "url" {
+		t.Errorf("arguments = %+v, want url", doc.Arguments)
+	}
+	if len(doc.Examples) != 1 || doc.Examples[0].Command != "myapp https://example.com" {
+		t.Errorf("examples = %+v, want one example", doc.Examples)
+	}
+	if len(doc.Sections) != 1 || doc.Sections[0].Position != "bottom" {
+		t.Errorf("sections = %+v, want one bottom section", doc.Sections)
+	}
+}
+
+func TestJSONFormatter_PrintError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   ErrorKind
+		wantOption string
+	}{
+		{
+			name:     "plain error",
+			err:      errors.New("something failed"),
+			wantKind: ErrorKindGeneric,
+		},
+		{
+			name:       "usage error",
+			err:        &UsageError{Kind: ErrorKindUnknownOption, Option: "timout", Message: "unknown", Suggestions: []string{"--timeout"}},
+			wantKind:   ErrorKindUnknownOption,
+			wantOption: "timout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var outBuf, errBuf bytes.Buffer
+			formatter := &JSONFormatter{
+				Output:        &outBuf,
+				Error:         &errBuf,
+				Configuration: &Configuration{ApplicationName: "myapp", Groups: map[string]*Group{}},
+			}
+
+			formatter.PrintError(tt.err)
+
+			if outBuf.Len() != 0 {
+				t.Errorf("PrintError() wrote to the output writer: %q", outBuf.String())
+			}
+			var doc jsonError
+			if err := json.Unmarshal(errBuf.Bytes(), &doc); err != nil {
+				t.Fatalf("PrintError() wrote invalid JSON: %v\n%s", err, errBuf.String())
+			}
+			if doc.Error.Kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", doc.Error.Kind, tt.wantKind)
+			}
+			if doc.Error.Option != tt.wantOption {
+				t.Errorf("option = %q, want %q", doc.Error.Option, tt.wantOption)
+			}
+			if want := NewUsageError(tt.err).Message; doc.Error.Message != want {
+				t.Errorf("message = %q, want %q", doc.Error.Message, want)
+			}
+			if doc.Error.Suggestions == nil {
+				t.Error("suggestions should be an empty list, not null")
+			}
+		})
+	}
+}
+
+func TestJSONFormatter_PrintErrorList(t *testing.T) {
+	var errBuf bytes.Buffer
+	formatter := &JSONFormatter{Error: &errBuf, Configuration: &Configuration{}}
+	formatter.PrintError(ErrorList{
+		&UsageError{Kind: ErrorKindInvalidValue, Option: "port", Message: "bad port",
+			Err: &InvalidValueError{Location: Location{Token: "abc", Index: 2}}},
+		&UsageError{Kind: ErrorKindMissingOption, Option: "name", Message: "missing name"},
+	})
+
+	var doc jsonError
+	if err := json.Unmarshal(errBuf.Bytes(), &doc); err != nil {
+		t.Fatalf("PrintError() wrote invalid JSON: %v\n%s", err, errBuf.String())
+	}
+	if doc.Error.Option != "port" || doc.Error.Token != "abc" || doc.Error.Index == nil || *doc.Error.Index != 2 {
+		t.Errorf("error = %+v, want the located first error", doc.Error)
+	}
+	if len(doc.Errors) != 2 || doc.Errors[1].Option != "name" || doc.Errors[1].Index != nil {
+		t.Errorf("errors = %+v, want both errors", doc.Errors)
+	}
+}
+
+func TestJSONFormatter_PrintErrorNone(t *testing.T) {
+	for _, err := range []error{nil, ErrorList{}} {
+		var outBuf, errBuf bytes.Buffer
+		formatter := &JSONFormatter{Output: &outBuf, Error: &errBuf, Configuration: &Configuration{}}
+		formatter.PrintError(err)
+		if outBuf.Len() != 0 || errBuf.Len() != 0 {
+			t.Errorf("PrintError(%#v) wrote %q and %q, want nothing", err, outBuf.String(), errBuf.String())
+		}
+	}
+}
+
+func TestJSONFormatter_PrintWarning(t *testing.T) {
+	var errBuf bytes.Buffer
+	formatter := &JSONFormatter{Error: &errBuf, Configuration: &Configuration{}}
+
+	formatter.PrintWarning("option --out is deprecated")
+
+	var doc jsonWarning
+	if err := json.Unmarshal(errBuf.Bytes(), &doc); err != nil {
+		t.Fatalf("PrintWarning() wrote invalid JSON: %v\n%s", err, errBuf.String())
+	}
+	if doc.Warning.Message != "option --out is deprecated" {
+		t.Errorf("message = %q, want %q", doc.Warning.Message, "option --out is deprecated")
+	}
+}
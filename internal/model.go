@@ -0,0 +1,100 @@
+package internal
+
+import "fmt"
+
+// Model is a read-only snapshot of the command line interface passed to a
+// ModelFormatter. It contains the application metadata and the groups,
+// options and arguments that are visible in the usage output, in display
+// order. The model holds copies, so changing it does not affect parsing, and
+// the defaults of secret options are replaced by SecretMask.
+type Model struct {
+	Name        string       // Name of the application
+	Version     string       // Version string
+	BuildDate   string       // Build date/timestamp
+	CommitHash  string       // Git commit hash
+	Branch      string       // Git branch name
+	Modified    bool         // Whether the build contains uncommitted changes
+	Description string       // Application description
+	ShowAll     bool         // Whether advanced and deprecated options are included (--help-all)
+	ErrorMode   ErrorMode    // How errors should be presented, ErrorModeConcise when empty
+	Groups      []ModelGroup // Groups with at least one visible option, ordered by priority
+	Arguments   []Argument   // Positional arguments ordered by position
+	Examples    []Example    // Example invocations
+	Sections    []Section    // Custom sections in the order they were added
+}
+
+// ModelGroup is the read-only representation of a Group in a Model.
+type ModelGroup struct {
+	Name        string   // Name of the group
+	Description string   // Description shown next to the group name
+	Priority    int      // Display priority, lower numbers first
+	Options     []Option // Visible options in the order they were added
+}
+
+// NewModel returns a snapshot of the configuration as shown in the usage
+// output. The Advanced and Deprecated settings of a group are copied onto its
+// options, so formatters do not need to look them up.
+func NewModel(c *Configuration) *Model {
+	m := &Model{
+		Name:        c.ApplicationName,
+		Version:     c.ApplicationVersion,
+		BuildDate:   c.ApplicationBuildDate,
+		CommitHash:  c.ApplicationCommitHash,
+		Branch:      c.ApplicationBranch,
+		Modified:    c.ApplicationModified,
+		Description: c.ApplicationDescription,
+		ShowAll:     c.ShowAll,
+		ErrorMode:   c.ErrorMode,
+	}
+
+	for _, group := range c.SortedGroups() {
+		options := group.VisibleOptions(c.ShowAll)
+		if len(options) == 0 {
+			continue
+		}
+		g := ModelGroup{
+			Name:        group.Name,
+			Description: group.Description,
+			Priority:    group.Priority,
+		}
+		for _, option := range options {
+			o := *option
+			o.Choices = append([]string(nil), option.Choices...)
+			o.Aliases = append([]string(nil), option.Aliases...)
+			o.RenamedFrom = append([]string(nil), option.RenamedFrom...)
+			o.RenamedEnv = append([]string(nil), option.RenamedEnv...)
+			o.Advanced = option.Advanced || group.Advanced
+			if option.Secret && option.Default != nil && fmt.Sprint(option.Default) != "" {
+				o.Default = SecretMask
+			}
+			if o.Deprecated == "" {
+				o.Deprecated = group.Deprecated
+			}
+			g.Options = append(g.Options, o)
+		}
+		m.Groups = append(m.Groups, g)
+	}
+
+	for _, argument := range c.SortedArguments() {
+		m.Arguments = append(m.Arguments, *argument)
+	}
+	for _, example := range c.Examples {
+		m.Examples = append(m.Examples, *example)
+	}
+	for _, section := range c.Sections {
+		m.Sections = append(m.Sections, *section)
+	}
+	return m
+}
+
+// SectionsAt returns the custom sections of the model that are rendered at the
+// given position in the order they were added.
+func (m *Model) SectionsAt(position SectionPosition) []Section {
+	var sections []Section
+	for _, section := range m.Sections {
+		if section.Position == position {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
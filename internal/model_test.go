@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newModelConfiguration() *Configuration {
+	return &Configuration{
+		ApplicationName:    "app",
+		ApplicationVersion: "1.0.0",
+		Groups: map[string]*Group{
+			"Default": {
+				Priority: 0,
+				Name:     "Default",
+				Options: []*Option{
+					{Short: "v", Long: "verbose", Default: false, Description: "Verbose output"},
+					{Long: "internal", Default: "", Hidden: true},
+				},
+				Arguments: []*Argument{
+					{Position: 2, Name: "dest"},
+					{Position: 1, Name: "src"},
+				},
+			},
+			"Legacy": {
+				Priority:   1,
+				Name:       "Legacy",
+				Deprecated: "will be removed",
+				Options: []*Option{
+					{Long: "old", Default: "", Choices: []string{"a", "b"}},
+				},
+			},
+		},
+		Examples: []*Example{{Command: "app -v"}},
+		Sections: []*Section{
+			{Title: "Notes", Body: "note", Position: SectionTop},
+			{Title: "Bugs", Body: "bugs", Position: SectionBottom},
+		},
+	}
+}
+
+func TestNewModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		showAll bool
+		groups  []string
+		options []string
+	}{
+		{"visible only", false, []string{"Default"}, []string{"verbose"}},
+		{"show all", true, []string{"Default", "Legacy"}, []string{"verbose", "old"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newModelConfiguration()
+			c.ShowAll = tt.showAll
+			m := NewModel(c)
+
+			var groups, options []string
+			for _, group := range m.Groups {
+				groups = append(groups, group.Name)
+				for _, option := range group.Options {
+					options = append(options, option.Long)
+				}
+			}
+			if !reflect.DeepEqual(groups, tt.groups) {
+				t.Errorf("groups = %q, want %q", groups, tt.groups)
+			}
+			if !reflect.DeepEqual(options, tt.options) {
+				t.Errorf("options = %q, want %q", options, tt.options)
+			}
+			if m.Name != "app" || m.Version != "1.0.0" || m.ShowAll != tt.showAll {
+				t.Errorf("metadata = %q %q %v", m.Name, m.Version, m.ShowAll)
+			}
+			if len(m.Arguments) != 2 || m.Arguments[0].Name != "src" {
+				t.Errorf("arguments = %+v, want src first", m.Arguments)
+			}
+		})
+	}
+}
+
+func TestNewModel_Copies(t *testing.T) {
+	c := newModelConfiguration()
+	c.ShowAll = true
+	m := NewModel(c)
+
+	legacy := m.Groups[1].Options[0]
+	if legacy.Deprecated != "will be removed" {
+		t.Errorf("Deprecated = %q, want the group deprecation", legacy.Deprecated)
+	}
+
+	m.Groups[0].Options[0].Description = "changed"
+	m.Groups[1].Options[0].Choices[0] = "changed"
+	m.Arguments[0].Name = "changed"
+	if c.Groups["Default"].Options[0].Description != "Verbose output" ||
+		c.Groups["Legacy"].Options[0].Choices[0] != "a" ||
+		c.Groups["Default"].Arguments[1].Name != "src" {
+		t.Error("changing the model modified the configuration")
+	}
+}
+
+func TestNewModel_SecretDefault(t *testing.T) {
+	c := newModelConfiguration()
+	c.Groups["Default"].Options = append(c.Groups["Default"].Options,
+		&Option{Long: "token", Default: "s3cret", Secret: true},
+		&Option{Long: "pin", Default: "", Secret: true},
+	)
+	m := NewModel(c)
+
+	defaults := map[string]interface{}{}
+	for _, option := range m.Groups[0].Options {
+		defaults[option.Long] = option.Default
+	}
+	if defaults["token"] != SecretMask {
+		t.Errorf("token default = %v, want %q", defaults["token"], SecretMask)
+	}
+	if defaults["pin"] != "" {
+		t.Errorf("pin default = %v, want it empty", defaults["pin"])
+	}
+	if c.Groups["Default"].Options[2].Default != "s3cret" {
+		t.Error("masking the model modified the configuration")
+	}
+}
+
+func TestModel_SectionsAt(t *testing.T) {
+	m := NewModel(newModelConfiguration())
+	if got := m.SectionsAt(SectionBottom); len(got) != 1 || got[0].Title != "Bugs" {
+		t.Errorf("SectionsAt(SectionBottom) = %+v, want Bugs", got)
+	}
+	if got := m.SectionsAt(SectionBeforeOptions); len(got) != 0 {
+		t.Errorf("SectionsAt(SectionBeforeOptions) = %+v, want none", got)
+	}
+}
+
+// recordingFormatter is a ModelFormatter that writes what it receives.
+type recordingFormatter struct {
+	err error
+}
+
+func (f *recordingFormatter) FormatUsage(w Writers, model *Model) error {
+	_, _ = w.Output.Write([]byte("usage " + model.Name + "\n"))
+	return f.err
+}
+
+func (f *recordingFormatter) FormatError(w Writers, model *Model, err error) error {
+	_, _ = w.Error.Write([]byte("error " + err.Error() + "\n"))
+	return f.err
+}
+
+func (f *recordingFormatter) FormatWarning(w Writers, model *Model, message string) error {
+	_, _ = w.Error.Write([]byte("warning " + message + "\n"))
+	return f.err
+}
+
+func TestModelFormatterAdapter(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantOut string
+		wantErr string
+	}{
+		{"success", nil, "usage app\n", "error bad\nwarning old\n"},
+		{"formatter error", errors.New("broken"), "usage app\n",
+			"formatter error: broken\nerror bad\nformatter error: broken\nwarning old\nformatter error: broken\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			adapter := &ModelFormatterAdapter{
+				Formatter:     &recordingFormatter{err: tt.err},
+				Output:        &out,
+				Error:         &errOut,
+				Configuration: newModelConfiguration(),
+			}
+			adapter.PrintUsage()
+			adapter.PrintError(errors.New("bad"))
+			adapter.PrintWarning("old")
+
+			if out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+			if errOut.String() != tt.wantErr {
+				t.Errorf("error output = %q, want %q", errOut.String(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// negationPrefix is the prefix that turns the long name of a boolean option
+// into its negated form, e.g. --no-verbose sets --verbose to false.
+const negationPrefix = "no-"
+
+// ArgNormalizer rewrites command-line arguments into the form understood by
+// the standard flag package before they are parsed. When enabled, it resolves
+// negated boolean options (--no-verbose) and unambiguous prefixes of long
+// option names (--verb for --verbose).
+type ArgNormalizer struct {
+	FlagSet       *flag.FlagSet  // Flag set the options are registered with
+	Configuration *Configuration // Configuration used to find hidden options
+	Abbreviations bool           // Resolve unique prefixes of long option names
+	Negation      bool           // Resolve the negated form of boolean options
+}
+
+// Normalize returns the arguments with negated and abbreviated options replaced
+// by their full names. Processing stops at the first positional argument or at
+// "--", like the flag package does. Unknown options are left unchanged so that
+// the flag package reports them. An ambiguous abbreviation results in a
+// UsageError listing the candidates.
+func (n *ArgNormalizer) Normalize(args []string) ([]string, error) {
+	result := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			return append(result, args[i:]...), nil
+		}
+
+		dashes := "-"
+		if strings.HasPrefix(arg, "--") {
+			dashes = "--"
+		}
+		name, value, hasValue := strings.Cut(arg[len(dashes):], "=")
+
+		resolved, negated, err := n.resolve(name, dashes == "--")
+		if err != nil {
+			err.Err = &AmbiguousOptionError{
+				Location:   Location{Token: arg, Index: i},
+				Name:       name,
+				Candidates: err.Suggestions,
+			}
+			return nil, err
+		}
+		if resolved == "" {
+			result = append(result, arg)
+			continue
+		}
+
+		f := n.FlagSet.Lookup(resolved)
+		switch {
+		case negated && hasValue:
+			return nil, &UsageError{
+				Kind:    ErrorKindInvalidValue,
+				Option:  name,
+				Message: fmt.Sprintf("option %s%s does not take a value", dashes, name),
+				Err: &InvalidValueError{
+					Location: Location{Token: arg, Index: i},
+					Option:   n.option(resolved),
+					Name:     name,
+					Value:    value,
+					Err:      errors.New("does not take a value"),
+				},
+			}
+		case negated:
+			result = append(result, dashes+resolved+"=false")
+		case hasValue:
+			result = append(result, dashes+resolved+"="+value)
+		default:
+			result = append(result, dashes+resolved)
+			// Copy the value of a non-boolean option so it is not mistaken for an option
+			if !isBoolFlag(f) && i+1 < len(args) {
+				i++
+				result = append(result, args[i])
+			}
+		}
+	}
+	return result, nil
+}
+
+// resolve returns the name of the flag that name refers to and whether it is
+// the negated form of a boolean option. An empty name is returned when the
+// option is unknown.
+func (n *ArgNormalizer) resolve(name string, allowAbbreviation bool) (string, bool, *UsageError) {
+	// An exact match always takes precedence
+	if n.FlagSet.Lookup(name) != nil {
+		return name, false, nil
+	}
+	if base := strings.TrimPrefix(name, negationPrefix); n.Negation && base != name && len(base) > 1 && isBoolFlag(n.FlagSet.Lookup(base)) {
+		return base, true, nil
+	}
+	if !n.Abbreviations || !allowAbbreviation || name == "" {
+		return "", false, nil
+	}
+
+	// Collect all long names, and the negated forms of boolean options, that start with name
+	var candidates []string
+	targets := map[string]string{}
+	n.FlagSet.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) < 2 || n.hidden(f.Name) || n.alias(f.Name) {
+			return
+		}
+		forms := []string{f.Name}
+		if n.Negation && isBoolFlag(f) && n.FlagSet.Lookup(negationPrefix+f.Name) == nil {
+			forms = append(forms, negationPrefix+f.Name)
+		}
+		for _, form := range forms {
+			if strings.HasPrefix(form, name) {
+				candidates = append(candidates, form)
+				targets[form] = f.Name
+			}
+		}
+	})
+
+	switch len(candidates) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return targets[candidates[0]], candidates[0] != targets[candidates[0]], nil
+	}
+
+	sort.Strings(candidates)
+	err := &UsageError{
+		Kind:    ErrorKindAmbiguousOption,
+		Option:  name,
+		Message: fmt.Sprintf("option --%s is ambiguous", name),
+	}
+	for _, candidate := range candidates {
+		err.Suggestions = append(err.Suggestions, "--"+candidate)
+	}
+	return "", false, err
+}
+
+// option returns the definition of the option registered under name, if any.
+func (n *ArgNormalizer) option(name string) *Option {
+	if n.Configuration == nil {
+		return nil
+	}
+	option, _ := n.Configuration.FindOption(name)
+	return option
+}
+
+// alias reports whether name is an alias or former name of an option, those
+// are never completed from an abbreviation so that an abbreviation is not
+// ambiguous between the names of a single option.
+func (n *ArgNormalizer) alias(name string) bool {
+	option := n.option(name)
+	return option != nil && name != option.Short && name != option.Long
+}
+
+// hidden reports whether the option registered under name is hidden, hidden
+// options are never completed from an abbreviation.
+func (n *ArgNormalizer) hidden(name string) bool {
+	if n.Configuration == nil {
+		return false
+	}
+	option, group := n.Configuration.FindOption(name)
+	return option != nil && (option.Hidden || group.Hidden)
+}
+
+// isBoolFlag reports whether f is a boolean flag which does not take a value.
+func isBoolFlag(f *flag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+)
+
+// newNormalizerFlagSet returns a flag set with a mix of boolean and value options.
+func newNormalizerFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("verbose", false, "")
+	fs.Bool("v", false, "")
+	fs.Bool("version", false, "")
+	fs.Bool("color", true, "")
+	fs.Bool("no-cache", false, "")
+	fs.Int("timeout", 10, "")
+	fs.Int("timeout-secs", 10, "")
+	fs.String("output", "", "")
+	fs.String("out", "", "")
+	fs.String("secret-level", "", "")
+	return fs
+}
+
+func TestArgNormalizer_Normalize(t *testing.T) {
+	config := &Configuration{
+		Groups: map[string]*Group{
+			"Default": {Name: "Default", Options: []*Option{
+				{Long: "secret-level", Hidden: true},
+				{Long: "timeout", RenamedFrom: []string{"timeout-secs"}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		abbreviations bool
+		negation      bool
+		args          []string
+		want          []string
+		wantAmbiguous []string
+	}{
+		{
+			name: "exact names unchanged",
+			args: []string{"--verbose", "--timeout", "5", "-v", "file"},
+			want: []string{"--verbose", "--timeout", "5", "-v", "file"},
+		},
+		{
+			name:     "negated boolean",
+			negation: true,
+			args:     []string{"--no-color", "-no-verbose"},
+			want:     []string{"--color=false", "-verbose=false"},
+		},
+		{
+			name:     "exact negative option wins over negation",
+			negation: true,
+			args:     []string{"--no-cache"},
+			want:     []string{"--no-cache"},
+		},
+		{
+			name: "negation disabled",
+			args: []string{"--no-color"},
+			want: []string{"--no-color"},
+		},
+		{
+			name: "abbreviations disabled",
+			args: []string{"--verb"},
+			want: []string{"--verb"},
+		},
+		{
+			name:          "unique prefix",
+			abbreviations: true,
+			args:          []string{"--verb", "--time", "5", "--col"},
+			want:          []string{"--verbose", "--timeout", "5", "--color"},
+		},
+		{
+			name:          "former names are not abbreviated",
+			abbreviations: true,
+			args:          []string{"--timeo", "5", "--timeout-s", "6"},
+			want:          []string{"--timeout", "5", "--timeout-s", "6"},
+		},
+		{
+			name:          "prefix with value",
+			abbreviations: true,
+			args:          []string{"--time=5"},
+			want:          []string{"--timeout=5"},
+		},
+		{
+			name:          "exact match is never abbreviated",
+			abbreviations: true,
+			args:          []string{"--out", "a.txt"},
+			want:          []string{"--out", "a.txt"},
+		},
+		{
+			name:          "abbreviated negation",
+			abbreviations: true,
+			negation:      true,
+			args:          []string{"--no-col", "--no-verb"},
+			want:          []string{"--color=false", "--verbose=false"},
+		},
+		{
+			name:          "negation prefix ambiguous with option",
+			abbreviations: true,
+			negation:      true,
+			args:          []string{"--no-c"},
+			wantAmbiguous: []string{"--no-cache", "--no-color"},
+		},
+		{
+			name:          "ambiguous prefix",
+			abbreviations: true,
+			args:          []string{"--ver"},
+			wantAmbiguous: []string{"--verbose", "--version"},
+		},
+		{
+			name:          "single dash is not abbreviated",
+			abbreviations: true,
+			args:          []string{"-verb"},
+			want:          []string{"-verb"},
+		},
+		{
+			name:          "hidden options are not abbreviated",
+			abbreviations: true,
+			args:          []string{"--secret"},
+			want:          []string{"--secret"},
+		},
+		{
+			name:          "value that looks like an option",
+			abbreviations: true,
+			args:          []string{"--outp", "--verb"},
+			want:          []string{"--output", "--verb"},
+		},
+		{
+			name:          "stops at first positional argument",
+			abbreviations: true,
+			args:          []string{"--verb", "file", "--time"},
+			want:          []string{"--verbose", "file", "--time"},
+		},
+		{
+			name:          "stops at terminator",
+			abbreviations: true,
+			args:          []string{"--", "--verb"},
+			want:          []string{"--", "--verb"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizer := &ArgNormalizer{
+				FlagSet:       newNormalizerFlagSet(),
+				Configuration: config,
+				Abbreviations: tt.abbreviations,
+				Negation:      tt.negation,
+			}
+
+			got, err := normalizer.Normalize(tt.args)
+			if tt.wantAmbiguous != nil {
+				var usageErr *UsageError
+				if !errors.As(err, &usageErr) || usageErr.Kind != ErrorKindAmbiguousOption {
+					t.Fatalf("Normalize() error = %v, want an ambiguous option error", err)
+				}
+				if !reflect.DeepEqual(usageErr.Suggestions, tt.wantAmbiguous) {
+					t.Errorf("Suggestions = %q, want %q", usageErr.Suggestions, tt.wantAmbiguous)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgNormalizer_NegationWithValue(t *testing.T) {
+	normalizer := &ArgNormalizer{FlagSet: newNormalizerFlagSet(), Negation: true}
+	_, err := normalizer.Normalize([]string{"--no-verbose=true"})
+
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) || usageErr.Kind != ErrorKindInvalidValue {
+		t.Errorf("Normalize() error = %v, want an invalid value error", err)
+	}
+}
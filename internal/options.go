@@ -1,5 +1,13 @@
 package internal
 
+import (
+	"fmt"
+	"strings"
+)
+
+// SecretMask replaces the values of secret options wherever they are displayed.
+const SecretMask = "******"
+
 // Option represents a command-line flag with short and long forms.
 // Options are registered with Go's flag package and displayed in usage output.
 type Option struct {
@@ -8,4 +16,152 @@ type Option struct {
 	Default     interface{} // Default value if the flag is not provided
 	Description string      // Help text describing the option
 	Extra       string      // Additional information shown in usage output
+	Hidden      bool        // Option is never shown in usage output but still parsed
+	Advanced    bool        // Option is only shown when all options are requested (--help-all)
+	Deprecated  string      // Deprecation message, a non-empty value marks the option as deprecated
+	Replacement string      // Name of the option that replaces a deprecated option, if any
+	Env         string      // Environment variable providing the value when the option is not given
+	Required    bool        // Option must be provided on the command line or through Env
+	Type        string      // Name of the value type, derived from Default when empty
+	Choices     []string    // Values the option accepts, any value is accepted when empty
+	Secret      bool        // Value is sensitive, e.g. it is read without echo when prompted for
+	Aliases     []string    // Additional short or long names accepted for the option
+	RenamedFrom []string    // Former names that still work but emit a deprecation warning
+	RenamedEnv  []string    // Former environment variables read, with a deprecation warning, when Env is not set
+}
+
+// DisplayValue returns value as it may be displayed, which is SecretMask for
+// non-empty values of secret options and the value itself otherwise.
+func (o *Option) DisplayValue(value string) string {
+	if o.Secret && value != "" {
+		return SecretMask
+	}
+	return value
+}
+
+// AllowsValue reports whether value is one of the choices of the option, or
+// true if the option has no choices.
+func (o *Option) AllowsValue(value string) bool {
+	if len(o.Choices) == 0 {
+		return true
+	}
+	for _, choice := range o.Choices {
+		if value == choice {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDeprecated reports whether the option has been marked as deprecated.
+func (o *Option) IsDeprecated() bool {
+	return o.Deprecated != ""
+}
+
+// DeprecationWarning returns the warning shown when a deprecated option is used
+// on the command line under the given name.
+func (o *Option) DeprecationWarning(name string) string {
+	warning := fmt.Sprintf("option %s is deprecated", Dashed(name))
+	if o.Deprecated != "" {
+		warning += ": " + o.Deprecated
+	}
+	if o.Replacement != "" {
+		warning += fmt.Sprintf(", use %s instead", Dashed(o.Replacement))
+	}
+	return warning
+}
+
+// HelpDescription returns the description shown in usage output, annotated
+// with whether the option is required, its environment variable and its
+// deprecation status.
+func (o *Option) HelpDescription() string {
+	description := o.Description
+	if len(o.Choices) > 0 {
+		description += fmt.Sprintf(" (one of: %s)", strings.Join(o.Choices, ", "))
+	}
+	if o.Required {
+		description += " (required)"
+	}
+	if len(o.Aliases) > 0 {
+		aliases := make([]string, len(o.Aliases))
+		for i, alias := range o.Aliases {
+			aliases[i] = Dashed(alias)
+		}
+		description += fmt.Sprintf(" [aliases: %s]", strings.Join(aliases, ", "))
+	}
+	if o.Env != "" {
+		description += fmt.Sprintf(" [env: %s]", o.Env)
+	}
+	if !o.IsDeprecated() {
+		return description
+	}
+	if o.Replacement != "" {
+		return fmt.Sprintf("%s (deprecated, use %s)", description, Dashed(o.Replacement))
+	}
+	return description + " (deprecated)"
+}
+
+// Name returns the long name of the option, or the short name if the option
+// has no long name.
+func (o *Option) Name() string {
+	if o.Long != "" {
+		return o.Long
+	}
+	return o.Short
+}
+
+// Names returns all names the option accepts: the short and long name, the
+// aliases and the former names.
+func (o *Option) Names() []string {
+	var names []string
+	for _, name := range []string{o.Short, o.Long} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	names = append(names, o.Aliases...)
+	return append(names, o.RenamedFrom...)
+}
+
+// HasName reports whether name is one of the names of the option.
+func (o *Option) HasName(name string) bool {
+	for _, n := range o.Names() {
+		if name != "" && n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRenamed reports whether name is a former name of the option.
+func (o *Option) IsRenamed(name string) bool {
+	for _, n := range o.RenamedFrom {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeName returns the name of the value type of the option derived from its
+// default value, e.g. "bool", "int", "float" or "string". An explicitly set
+// Type takes precedence.
+func (o *Option) TypeName() string {
+	if o.Type != "" {
+		return o.Type
+	}
+	switch o.Default.(type) {
+	case bool:
+		return "bool"
+	case int, int64:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%T", o.Default)
+	}
 }
@@ -1,6 +1,9 @@
 package internal
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestOption_Creation(t *testing.T) {
 	tests := []struct {
@@ -107,3 +110,163 @@ func TestOption_EmptyNames(t *testing.T) {
 		}
 	})
 }
+
+func TestOption_TypeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+		want   string
+	}{
+		{"bool", Option{Default: false}, "bool"},
+		{"int", Option{Default: 8080}, "int"},
+		{"float", Option{Default: 1.5}, "float"},
+		{"string", Option{Default: "out.txt"}, "string"},
+		{"nil", Option{}, ""},
+		{"other", Option{Default: []string{"a"}}, "[]string"},
+		{"explicit type", Option{Default: "30s", Type: "duration"}, "duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.option.TypeName(); got != tt.want {
+				t.Errorf("TypeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOption_Deprecation(t *testing.T) {
+	tests := []struct {
+		name            string
+		option          Option
+		usedName        string
+		wantWarning     string
+		wantDescription string
+	}{
+		{
+			name:            "not deprecated",
+			option:          Option{Long: "output", Description: "Output file"},
+			usedName:        "output",
+			wantWarning:     "option --output is deprecated",
+			wantDescription: "Output file",
+		},
+		{
+			name:            "with message and replacement",
+			option:          Option{Short: "o", Long: "out", Description: "Output file", Deprecated: "renamed", Replacement: "output"},
+			usedName:        "o",
+			wantWarning:     "option -o is deprecated: renamed, use --output instead",
+			wantDescription: "Output file (deprecated, use --output)",
+		},
+		{
+			name:            "with message only",
+			option:          Option{Long: "legacy", Description: "Legacy mode", Deprecated: "no longer needed"},
+			usedName:        "legacy",
+			wantWarning:     "option --legacy is deprecated: no longer needed",
+			wantDescription: "Legacy mode (deprecated)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.option.DeprecationWarning(tt.usedName); got != tt.wantWarning {
+				t.Errorf("DeprecationWarning() = %q, want %q", got, tt.wantWarning)
+			}
+			if got := tt.option.HelpDescription(); got != tt.wantDescription {
+				t.Errorf("HelpDescription() = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestOption_HelpDescription(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+		want   string
+	}{
+		{"plain", Option{Description: "Timeout"}, "Timeout"},
+		{"required", Option{Description: "Timeout", Required: true}, "Timeout (required)"},
+		{"env", Option{Description: "Timeout", Env: "TIMEOUT"}, "Timeout [env: TIMEOUT]"},
+		{"choices", Option{Description: "Format", Choices: []string{"json", "text"}}, "Format (one of: json, text)"},
+		{"aliases", Option{Description: "Output", Aliases: []string{"O", "out-file"}, RenamedFrom: []string{"out"}, Env: "OUT"}, "Output [aliases: -O, --out-file] [env: OUT]"},
+		{"required env deprecated", Option{Description: "Timeout", Required: true, Env: "TIMEOUT", Deprecated: "old"}, "Timeout (required) [env: TIMEOUT] (deprecated)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.option.HelpDescription(); got != tt.want {
+				t.Errorf("HelpDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOption_AllowsValue(t *testing.T) {
+	option := &Option{Choices: []string{"json", "text"}}
+	if !option.AllowsValue("json") {
+		t.Errorf("AllowsValue(%q) = false, want true", "json")
+	}
+	if option.AllowsValue("yaml") {
+		t.Errorf("AllowsValue(%q) = true, want false", "yaml")
+	}
+	if !(&Option{}).AllowsValue("anything") {
+		t.Errorf("AllowsValue() without choices = false, want true")
+	}
+}
+
+func TestOption_DisplayValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+		value  string
+		want   string
+	}{
+		{"regular", Option{}, "abc", "abc"},
+		{"secret", Option{Secret: true}, "abc", SecretMask},
+		{"empty secret", Option{Secret: true}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.option.DisplayValue(tt.value); got != tt.want {
+				t.Errorf("DisplayValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOption_Name(t *testing.T) {
+	if got := (&Option{Short: "t", Long: "timeout"}).Name(); got != "timeout" {
+		t.Errorf("Name() = %q, want %q", got, "timeout")
+	}
+	if got := (&Option{Short: "t"}).Name(); got != "t" {
+		t.Errorf("Name() = %q, want %q", got, "t")
+	}
+}
+
+func TestOption_Names(t *testing.T) {
+	option := &Option{Short: "o", Long: "output", Aliases: []string{"O"}, RenamedFrom: []string{"out"}}
+	if got, want := option.Names(), []string{"o", "output", "O", "out"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name        string
+		wantHasName bool
+		wantRenamed bool
+	}{
+		{"output", true, false},
+		{"O", true, false},
+		{"out", true, true},
+		{"", false, false},
+		{"missing", false, false},
+	}
+	for _, tt := range tests {
+		if got := option.HasName(tt.name); got != tt.wantHasName {
+			t.Errorf("HasName(%q) = %v, want %v", tt.name, got, tt.wantHasName)
+		}
+		if got := option.IsRenamed(tt.name); got != tt.wantRenamed {
+			t.Errorf("IsRenamed(%q) = %v, want %v", tt.name, got, tt.wantRenamed)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Location identifies the command-line token an error refers to.
+type Location struct {
+	Token string // Raw token, e.g. "--port=abc", with the values of secret options masked
+	Index int    // Index of the token in the arguments, len(arguments) for the end of the line and -1 for none
+}
+
+// NoLocation is the Location of errors that do not refer to a token, e.g.
+// errors in values read from environment variables.
+var NoLocation = Location{Index: -1}
+
+// location returns l, it is promoted to the errors that embed a Location.
+func (l Location) location() Location {
+	return l
+}
+
+// UnknownOptionError reports an option that is not registered.
+type UnknownOptionError struct {
+	Location
+	Name string // Name of the option without dashes
+	Err  error  // Underlying error, if any
+}
+
+func (e *UnknownOptionError) Error() string {
+	return fmt.Sprintf("unknown option %s", Dashed(e.Name))
+}
+
+func (e *UnknownOptionError) Unwrap() error {
+	return e.Err
+}
+
+// AmbiguousOptionError reports an abbreviated option that matches several
+// long option names.
+type AmbiguousOptionError struct {
+	Location
+	Name       string   // Abbreviation without dashes
+	Candidates []string // Options the abbreviation matches, with dashes
+}
+
+func (e *AmbiguousOptionError) Error() string {
+	return fmt.Sprintf("option --%s is ambiguous (%s)", e.Name, strings.Join(e.Candidates, ", "))
+}
+
+// InvalidValueError reports a value that cannot be converted to the type of
+// its option.
+type InvalidValueError struct {
+	Location
+	Option *Option // Definition of the option, nil for options without one such as --version
+	Name   string  // Name of the option as it was used, without dashes
+	Value  string  // Offending value, masked for secret options
+	Err    error   // Conversion error
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s: %v", e.Value, Dashed(e.Name), e.Err)
+}
+
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// MissingValueError reports an option that requires a value but was given
+// without one.
+type MissingValueError struct {
+	Location
+	Option *Option // Definition of the option, nil for options without one
+	Name   string  // Name of the option as it was used, without dashes
+}
+
+func (e *MissingValueError) Error() string {
+	return fmt.Sprintf("option %s needs a value", Dashed(e.Name))
+}
+
+// MissingOptionError reports a required option that was not provided.
+type MissingOptionError struct {
+	Location
+	Option *Option // Definition of the option
+	Err    error   // Reason the value could not be obtained, e.g. from a prompt
+}
+
+func (e *MissingOptionError) Error() string {
+	return fmt.Sprintf("missing required option %s", Dashed(e.Option.Name()))
+}
+
+func (e *MissingOptionError) Unwrap() error {
+	return e.Err
+}
+
+// MissingArgumentError reports a required positional argument that was not
+// provided. Its Location is the end of the command line.
+type MissingArgumentError struct {
+	Location
+	Argument *Argument // Definition of the argument
+	Err      error     // Reason the value could not be obtained, e.g. from a prompt
+}
+
+func (e *MissingArgumentError) Error() string {
+	return fmt.Sprintf("missing required argument %s", e.Argument.Name)
+}
+
+func (e *MissingArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// ConstraintError reports a value that has the right type but violates a
+// constraint of its option, such as not being one of its choices.
+type ConstraintError struct {
+	Location
+	Option     *Option // Definition of the option
+	Value      string  // Offending value, masked for secret options
+	Constraint string  // Description of the violated constraint, e.g. "must be one of a, b"
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s: %s", e.Value, Dashed(e.Option.Name()), e.Constraint)
+}
+
+// ErrorList aggregates several errors that were found together, e.g. all
+// missing required options. errors.Is and errors.As match any of the errors.
+type ErrorList []error
+
+// NewErrorList returns nil when errs is empty, the only error when there is
+// one and an ErrorList of errs otherwise.
+func NewErrorList(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return ErrorList(errs)
+}
+
+// Error returns the messages of the errors, one per line.
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, err := range l {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Is reports whether any of the errors matches target.
+func (l ErrorList) Is(target error) bool {
+	for _, err := range l {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error that matches target.
+func (l ErrorList) As(target interface{}) bool {
+	for _, err := range l {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the errors, for versions of the errors package that support
+// multiple wrapped errors.
+func (l ErrorList) Unwrap() []error {
+	return l
+}
+
+// UsageErrors returns err as a list of UsageErrors, with ErrorLists flattened.
+// The list is empty if err is nil or an empty ErrorList.
+func UsageErrors(err error) []*UsageError {
+	if err == nil {
+		return nil
+	}
+	var list ErrorList
+	if errors.As(err, &list) {
+		var result []*UsageError
+		for _, e := range list {
+			result = append(result, UsageErrors(e)...)
+		}
+		return result
+	}
+	return []*UsageError{NewUsageError(err)}
+}
+
+// Location returns the location of the token the error refers to, or
+// NoLocation if the error does not refer to a token.
+func (e *UsageError) Location() Location {
+	var located interface{ location() Location }
+	if errors.As(e.Err, &located) {
+		return located.location()
+	}
+	return NoLocation
+}
+
+// Highlight returns the command line, prefixed with the program name, and a
+// line with carets under the token the error refers to. It returns nil when
+// the error does not refer to a token of the command line.
+func (e *UsageError) Highlight(program string) []string {
+	location := e.Location()
+	if location.Index < 0 || location.Index > len(e.Args) {
+		return nil
+	}
+
+	line := program
+	offset, width := 0, 1
+	for i, arg := range e.Args {
+		token := quoteToken(arg)
+		if i == location.Index {
+			offset, width = utf8.RuneCountInString(line)+1, utf8.RuneCountInString(token)
+		}
+		line += " " + token
+	}
+	if location.Index == len(e.Args) {
+		offset = utf8.RuneCountInString(line) + 1
+	}
+	if width == 0 {
+		width = 1
+	}
+	return []string{line, strings.Repeat(" ", offset) + strings.Repeat("^", width)}
+}
+
+// quoteToken quotes tokens that would not be read back as a single argument.
+func quoteToken(token string) string {
+	if token == "" || strings.ContainsAny(token, " \t\n\"'\\") {
+		return strconv.Quote(token)
+	}
+	return token
+}
+
+// Locate attaches the command line to a UsageError produced while
+// parsing args and wraps its underlying error in the typed error matching its
+// kind, located at the token at index. The values of secret options are masked
+// in the stored arguments.
+func (e *UsageError) Locate(c *Configuration, args []string, index int) {
+	e.Args = MaskArgs(c, args)
+	if index < 0 || index >= len(args) {
+		return
+	}
+	location := Location{Token: e.Args[index], Index: index}
+	option, _ := c.FindOption(e.Option)
+
+	switch e.Kind {
+	case ErrorKindUnknownOption:
+		e.Err = &UnknownOptionError{Location: location, Name: e.Option, Err: e.Err}
+	case ErrorKindMissingValue:
+		e.Err = &MissingValueError{Location: location, Option: option, Name: e.Option}
+	case ErrorKindInvalidValue:
+		value := location.Token
+		if strings.HasPrefix(value, "-") {
+			if _, v, ok := strings.Cut(value, "="); ok {
+				value = v
+			}
+		}
+		e.Err = &InvalidValueError{Location: location, Option: option, Name: e.Option, Value: value, Err: invalidValueReason(e.Err)}
+	}
+}
+
+// invalidValueReason returns the reason of an invalid value error of the flag
+// package, e.g. "parse error" for `invalid value "x" for flag -n: parse error`.
+func invalidValueReason(err error) error {
+	message := err.Error()
+	if i := strings.Index(message, " for "); i >= 0 {
+		if _, reason, ok := strings.Cut(message[i:], ": "); ok {
+			return errors.New(reason)
+		}
+	}
+	return err
+}
+
+// MaskArgs returns a copy of args with the values of secret options replaced
+// by SecretMask.
+func MaskArgs(c *Configuration, args []string) []string {
+	masked := append([]string(nil), args...)
+	scanOptions(c, args, func(option *Option, index, valueIndex int) {
+		if !option.Secret || valueIndex < 0 {
+			return
+		}
+		if valueIndex == index {
+			name, value, _ := strings.Cut(args[index], "=")
+			masked[index] = name + "=" + option.DisplayValue(value)
+		} else {
+			masked[valueIndex] = option.DisplayValue(args[valueIndex])
+		}
+	})
+	return masked
+}
+
+// FindToken returns the location of the value of the last occurrence of
+// option in args, which is the token itself for "--name=value" and booleans
+// and the following token for "--name value", or NoLocation if the option was
+// not given on the command line.
+func FindToken(c *Configuration, args []string, option *Option) Location {
+	location := NoLocation
+	scanOptions(c, args, func(o *Option, index, valueIndex int) {
+		if o != option {
+			return
+		}
+		if valueIndex < 0 {
+			valueIndex = index
+		}
+		location = Location{Token: args[valueIndex], Index: valueIndex}
+	})
+	return location
+}
+
+// scanOptions calls fn for each registered option in args up to the first
+// positional argument or "--", like the flag package parses them, with the
+// index of the option and the index of its value. The value index equals the
+// option index for "--name=value" and is -1 for booleans and missing values.
+func scanOptions(c *Configuration, args []string, fn func(option *Option, index, valueIndex int)) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			return
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		option, _ := c.FindOption(name)
+		if option == nil {
+			continue
+		}
+		switch {
+		case hasValue:
+			fn(option, i, i)
+		case option.TypeName() == "bool" || i+1 >= len(args):
+			fn(option, i, -1)
+		default:
+			fn(option, i, i+1)
+			i++
+		}
+	}
+}
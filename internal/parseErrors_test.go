@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newParseErrorsConfiguration() *Configuration {
+	return &Configuration{
+		Groups: map[string]*Group{
+			"Default": {Name: "Default", Options: []*Option{
+				{Short: "p", Long: "port", Default: 80},
+				{Short: "v", Long: "verbose", Default: false},
+				{Long: "token", Default: "", Secret: true},
+				{Long: "color", Default: "auto", Choices: []string{"auto", "never"}},
+			}},
+		},
+	}
+}
+
+func TestUsageError_Highlight(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		loc  Location
+		want []string
+	}{
+		{
+			name: "separate value",
+			args: []string{"-v", "--port", "abc"},
+			loc:  Location{Token: "abc", Index: 2},
+			want: []string{"app -v --port abc", "              ^^^"},
+		},
+		{
+			name: "quoted token",
+			args: []string{"--name=a b", "x"},
+			loc:  Location{Token: "--name=a b", Index: 0},
+			want: []string{`app "--name=a b" x`, "    ^^^^^^^^^^^^"},
+		},
+		{
+			name: "end of line",
+			args: []string{"-v"},
+			loc:  Location{Index: 1},
+			want: []string{"app -v", "       ^"},
+		},
+		{
+			name: "no location",
+			args: []string{"-v"},
+			loc:  NoLocation,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &UsageError{Args: tt.args, Err: &UnknownOptionError{Location: tt.loc}}
+			if got := err.Highlight("app"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageError_Locate(t *testing.T) {
+	c := newParseErrorsConfiguration()
+	tests := []struct {
+		name  string
+		err   *UsageError
+		args  []string
+		index int
+		check func(t *testing.T, err error)
+	}{
+		{
+			name:  "unknown option",
+			err:   NewUsageError(errors.New("flag provided but not defined: -prot")),
+			args:  []string{"-v", "-prot", "1"},
+			index: 1,
+			check: func(t *testing.T, err error) {
+				var target *UnknownOptionError
+				if !errors.As(err, &target) || target.Name != "prot" || target.Index != 1 || target.Token != "-prot" {
+					t.Errorf("UnknownOptionError = %+v", target)
+				}
+			},
+		},
+		{
+			name:  "invalid value",
+			err:   NewUsageError(errors.New(`invalid value "abc" for flag -port: parse error`)),
+			args:  []string{"--port=abc"},
+			index: 0,
+			check: func(t *testing.T, err error) {
+				var target *InvalidValueError
+				if !errors.As(err, &target) || target.Option == nil || target.Option.Long != "port" ||
+					target.Value != "abc" || target.Err.Error() != "parse error" {
+					t.Errorf("InvalidValueError = %+v", target)
+				}
+			},
+		},
+		{
+			name:  "secret value",
+			err:   NewUsageError(errors.New(`invalid value "hunter2" for flag -token: parse error`)),
+			args:  []string{"--token", "hunter2"},
+			index: 1,
+			check: func(t *testing.T, err error) {
+				var target *InvalidValueError
+				if !errors.As(err, &target) || target.Value != SecretMask || target.Token != SecretMask {
+					t.Errorf("InvalidValueError = %+v, want the value masked", target)
+				}
+			},
+		},
+		{
+			name:  "missing value",
+			err:   NewUsageError(errors.New("flag needs an argument: -port")),
+			args:  []string{"--port"},
+			index: 0,
+			check: func(t *testing.T, err error) {
+				var target *MissingValueError
+				if !errors.As(err, &target) || target.Option == nil || target.Index != 0 {
+					t.Errorf("MissingValueError = %+v", target)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.err.Locate(c, tt.args, tt.index)
+			tt.check(t, tt.err)
+			if location := tt.err.Location(); location.Index != tt.index {
+				t.Errorf("Location().Index = %d, want %d", location.Index, tt.index)
+			}
+		})
+	}
+}
+
+func TestMaskArgs(t *testing.T) {
+	c := newParseErrorsConfiguration()
+	args := []string{"-v", "--token", "s3cret", "--port", "80", "--token=other", "pos", "--token", "x"}
+	want := []string{"-v", "--token", SecretMask, "--port", "80", "--token=" + SecretMask, "pos", "--token", "x"}
+	if got := MaskArgs(c, args); !reflect.DeepEqual(got, want) {
+		t.Errorf("MaskArgs() = %q, want %q", got, want)
+	}
+	if args[2] != "s3cret" {
+		t.Error("MaskArgs() modified its argument")
+	}
+}
+
+func TestFindToken(t *testing.T) {
+	c := newParseErrorsConfiguration()
+	color, _ := c.FindOption("color")
+	verbose, _ := c.FindOption("verbose")
+	tests := []struct {
+		name   string
+		args   []string
+		option *Option
+		want   Location
+	}{
+		{"separate value", []string{"--port", "80", "--color", "red"}, color, Location{Token: "red", Index: 3}},
+		{"inline value", []string{"--color=red", "-v"}, color, Location{Token: "--color=red", Index: 0}},
+		{"last occurrence", []string{"--color", "auto", "--color=red"}, color, Location{Token: "--color=red", Index: 2}},
+		{"boolean", []string{"-p", "1", "-v"}, verbose, Location{Token: "-v", Index: 2}},
+		{"after positional", []string{"pos", "--color", "red"}, color, NoLocation},
+		{"not given", []string{"-v"}, color, NoLocation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindToken(c, tt.args, tt.option); got != tt.want {
+				t.Errorf("FindToken() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+var errSentinel = errors.New("sentinel")
+
+func TestErrorList(t *testing.T) {
+	missing := &UsageError{Kind: ErrorKindMissingOption, Message: "missing required option --a"}
+	wrapped := &UsageError{Kind: ErrorKindGeneric, Message: "b", Err: errSentinel}
+
+	if err := NewErrorList(nil); err != nil {
+		t.Errorf("NewErrorList(nil) = %v, want nil", err)
+	}
+	if err := NewErrorList([]error{missing}); err != missing {
+		t.Errorf("NewErrorList() = %v, want the only error", err)
+	}
+
+	err := NewErrorList([]error{missing, wrapped})
+	if got, want := err.Error(), "missing required option --a\nb"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, errSentinel) {
+		t.Error("errors.Is() = false, want a match in the second error")
+	}
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) || usageErr != missing {
+		t.Errorf("errors.As() = %v, want the first error", usageErr)
+	}
+	if got := UsageErrors(err); len(got) != 2 || got[1] != wrapped {
+		t.Errorf("UsageErrors() = %v, want both errors", got)
+	}
+}
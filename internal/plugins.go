@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Plugin is an external command found by FindPlugins.
+type Plugin struct {
+	Name string // Name of the command, e.g. "deploy" for "myapp-deploy"
+	Path string // Path of the executable
+}
+
+// FindPlugins returns the executables in dirs whose names start with prefix,
+// e.g. "myapp-", as plugins named after the rest of the file name, ordered by
+// name. When several directories contain a plugin with the same name the
+// first one wins, like the lookup of commands on PATH. On Windows the file
+// must have one of the extensions in PATHEXT, which is not part of the name.
+// Directories that cannot be read are skipped.
+func FindPlugins(prefix string, dirs []string) []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(prefix, entry.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// pluginName returns the command name of the executable file, which is the
+// file name without prefix and, on Windows, without its executable extension.
+func pluginName(prefix string, file string) (string, bool) {
+	if !strings.HasPrefix(file, prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, prefix)
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext == "" || !strings.Contains(strings.ToLower(pathExt()), ext+";") {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name != ""
+}
+
+// pathExt returns the executable extensions of Windows separated and
+// terminated by semicolons.
+func pathExt() string {
+	ext := os.Getenv("PATHEXT")
+	if ext == "" {
+		ext = ".com;.exe;.bat;.cmd"
+	}
+	return ext + ";"
+}
+
+// isExecutable reports whether path is a regular file that can be executed.
+// On Windows the extension has already been checked by pluginName.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are identified by their extension on Windows")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	files := []struct {
+		dir  string
+		name string
+		mode os.FileMode
+	}{
+		{first, "app-deploy", 0o755},
+		{first, "app-notes", 0o644},
+		{first, "other-tool", 0o755},
+		{first, "app-", 0o755},
+		{second, "app-deploy", 0o755},
+		{second, "app-backup", 0o700},
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(f.dir, f.name), []byte("#!/bin/sh\n"), f.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(first, "app-dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := FindPlugins("app-", []string{"", first, filepath.Join(first, "missing"), second})
+	want := []Plugin{
+		{Name: "backup", Path: filepath.Join(second, "app-backup")},
+		{Name: "deploy", Path: filepath.Join(first, "app-deploy")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindPlugins() = %v, want %v", got, want)
+	}
+}
+
+func TestPluginName(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin names keep their extension except on Windows")
+	}
+	tests := []struct {
+		file string
+		want string
+		ok   bool
+	}{
+		{"app-deploy", "deploy", true},
+		{"app-deploy.sh", "deploy.sh", true},
+		{"app-", "", false},
+		{"tool-deploy", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, ok := pluginName("app-", tt.file)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("pluginName(%q) = %q, %v, want %q, %v", tt.file, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
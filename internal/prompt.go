@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+)
+
+// maxPromptAttempts is the number of times a question is asked before giving
+// up on invalid or empty answers.
+const maxPromptAttempts = 3
+
+// ErrNoAnswer is returned when the user gives no valid answer to a prompt.
+var ErrNoAnswer = errors.New("no valid answer")
+
+// Prompter asks the user for the values of options and arguments that are
+// required but were not provided. Questions are written to Output and answers
+// read line by line from Input. Secret values are read with ReadSecret, which
+// should not echo the input, or from Input when ReadSecret is nil.
+type Prompter struct {
+	Input      io.Reader
+	Output     io.Writer
+	ReadSecret func() (string, error)
+
+	reader *bufio.Reader
+}
+
+// NewTerminalPrompter returns a Prompter reading from os.Stdin and writing to
+// os.Stderr, with secrets read without echo. It returns nil when stdin is not
+// a terminal, so that prompting is disabled in scripts, pipes and CI jobs.
+func NewTerminalPrompter() *Prompter {
+	fd := os.Stdin.Fd()
+	if !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd) {
+		return nil
+	}
+	return &Prompter{
+		Input:  os.Stdin,
+		Output: os.Stderr,
+		ReadSecret: func() (string, error) {
+			secret, err := term.ReadPassword(int(fd))
+			return string(secret), err
+		},
+	}
+}
+
+// PromptOption asks for the value of option and returns the answer in its
+// command line form. The question is the description of the option, the
+// default value is used for empty answers, options with choices are shown as
+// a numbered menu, booleans are asked as a yes/no confirmation and secret
+// options are read without echo.
+func (p *Prompter) PromptOption(option *Option) (string, error) {
+	label := option.Description
+	if label == "" {
+		label = Dashed(option.Name())
+	}
+	defaultValue := ""
+	if option.Default != nil && !option.Secret {
+		defaultValue = fmt.Sprint(option.Default)
+	}
+
+	switch {
+	case len(option.Choices) > 0:
+		return p.choose(label, option.Choices, defaultValue)
+	case option.TypeName() == "bool":
+		return p.confirm(label, defaultValue == "true")
+	}
+	return p.ask(label, defaultValue, option.Secret)
+}
+
+// PromptArgument asks for the value of a positional argument.
+func (p *Prompter) PromptArgument(argument *Argument) (string, error) {
+	label := argument.Description
+	if label == "" {
+		label = argument.Name
+	}
+	return p.ask(label, "", false)
+}
+
+// ask asks for a free-form value until a non-empty answer is given or the
+// default applies.
+func (p *Prompter) ask(label string, defaultValue string, secret bool) (string, error) {
+	for attempt := 0; attempt < maxPromptAttempts; attempt++ {
+		if defaultValue != "" {
+			fmt.Fprintf(p.Output, "%s [%s]: ", label, defaultValue)
+		} else {
+			fmt.Fprintf(p.Output, "%s: ", label)
+		}
+
+		var answer string
+		var err error
+		if secret && p.ReadSecret != nil {
+			answer, err = p.ReadSecret()
+			fmt.Fprintln(p.Output)
+		} else {
+			answer, err = p.readLine()
+		}
+		if err != nil {
+			return "", err
+		}
+		if answer = strings.TrimSpace(answer); answer != "" {
+			return answer, nil
+		}
+		if defaultValue != "" {
+			return defaultValue, nil
+		}
+	}
+	return "", fmt.Errorf("%w for %q", ErrNoAnswer, label)
+}
+
+// confirm asks a yes/no question and returns "true" or "false".
+func (p *Prompter) confirm(label string, defaultValue bool) (string, error) {
+	hint := "y/N"
+	if defaultValue {
+		hint = "Y/n"
+	}
+	for attempt := 0; attempt < maxPromptAttempts; attempt++ {
+		fmt.Fprintf(p.Output, "%s? [%s]: ", label, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return strconv.FormatBool(defaultValue), nil
+		case "y", "yes", "true":
+			return "true", nil
+		case "n", "no", "false":
+			return "false", nil
+		}
+	}
+	return "", fmt.Errorf("%w for %q", ErrNoAnswer, label)
+}
+
+// choose shows the choices as a numbered menu and returns the choice picked
+// by number or by value.
+func (p *Prompter) choose(label string, choices []string, defaultValue string) (string, error) {
+	for attempt := 0; attempt < maxPromptAttempts; attempt++ {
+		fmt.Fprintf(p.Output, "%s:\n", label)
+		for i, choice := range choices {
+			fmt.Fprintf(p.Output, "  %d) %s\n", i+1, choice)
+		}
+		if defaultValue != "" {
+			fmt.Fprintf(p.Output, "Choose 1-%d [%s]: ", len(choices), defaultValue)
+		} else {
+			fmt.Fprintf(p.Output, "Choose 1-%d: ", len(choices))
+		}
+
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" && defaultValue != "" {
+			return defaultValue, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		for _, choice := range choices {
+			if answer == choice {
+				return choice, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w for %q", ErrNoAnswer, label)
+}
+
+// readLine reads a single line from Input without the line terminator. An
+// empty input at the end of the stream returns io.ErrUnexpectedEOF.
+func (p *Prompter) readLine() (string, error) {
+	if p.reader == nil {
+		p.reader = bufio.NewReader(p.Input)
+	}
+	line, err := p.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err == io.EOF {
+		return "", io.ErrUnexpectedEOF
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPrompter_PromptOption(t *testing.T) {
+	tests := []struct {
+		name       string
+		option     *Option
+		input      string
+		want       string
+		wantOutput string
+	}{
+		{
+			name:       "text",
+			option:     &Option{Long: "name", Description: "Your name", Default: ""},
+			input:      "Ada\n",
+			want:       "Ada",
+			wantOutput: "Your name: ",
+		},
+		{
+			name:       "default",
+			option:     &Option{Long: "region", Description: "Region", Default: "us-east-1"},
+			input:      "\n",
+			want:       "us-east-1",
+			wantOutput: "Region [us-east-1]: ",
+		},
+		{
+			name:       "name without description",
+			option:     &Option{Long: "token"},
+			input:      "abc\n",
+			want:       "abc",
+			wantOutput: "--token: ",
+		},
+		{
+			name:       "empty answer is asked again",
+			option:     &Option{Long: "name", Description: "Name"},
+			input:      "\n  \nBob",
+			want:       "Bob",
+			wantOutput: "Name: Name: Name: ",
+		},
+		{
+			name:       "confirmation",
+			option:     &Option{Long: "force", Description: "Overwrite files", Default: false},
+			input:      "yes\n",
+			want:       "true",
+			wantOutput: "Overwrite files? [y/N]: ",
+		},
+		{
+			name:       "confirmation default",
+			option:     &Option{Long: "follow", Description: "Follow redirects", Default: true},
+			input:      "\n",
+			want:       "true",
+			wantOutput: "Follow redirects? [Y/n]: ",
+		},
+		{
+			name:       "menu by number",
+			option:     &Option{Long: "format", Description: "Output format", Choices: []string{"json", "text"}},
+			input:      "2\n",
+			want:       "text",
+			wantOutput: "Output format:\n  1) json\n  2) text\nChoose 1-2: ",
+		},
+		{
+			name:       "menu by value with default",
+			option:     &Option{Long: "format", Description: "Output format", Default: "json", Choices: []string{"json", "text"}},
+			input:      "yaml\ntext\n",
+			want:       "text",
+			wantOutput: "Output format:\n  1) json\n  2) text\nChoose 1-2 [json]: Output format:\n  1) json\n  2) text\nChoose 1-2 [json]: ",
+		},
+		{
+			name:       "secret default is not shown",
+			option:     &Option{Long: "token", Description: "API token", Default: "old", Secret: true},
+			input:      "s3cret\n",
+			want:       "s3cret",
+			wantOutput: "API token: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			p := &Prompter{Input: strings.NewReader(tt.input), Output: &out}
+			got, err := p.PromptOption(tt.option)
+			if err != nil {
+				t.Fatalf("PromptOption() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PromptOption() = %q, want %q", got, tt.want)
+			}
+			if out.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestPrompter_ReadSecret(t *testing.T) {
+	var out bytes.Buffer
+	p := &Prompter{
+		Input:      strings.NewReader("visible\n"),
+		Output:     &out,
+		ReadSecret: func() (string, error) { return "hidden", nil },
+	}
+	got, err := p.PromptOption(&Option{Long: "token", Description: "Token", Secret: true})
+	if err != nil {
+		t.Fatalf("PromptOption() error = %v", err)
+	}
+	if got != "hidden" {
+		t.Errorf("PromptOption() = %q, want %q", got, "hidden")
+	}
+	if out.String() != "Token: \n" {
+		t.Errorf("output = %q, want %q", out.String(), "Token: \n")
+	}
+}
+
+func TestPrompter_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  *Option
+		input   string
+		wantErr error
+	}{
+		{"end of input", &Option{Long: "name"}, "", io.ErrUnexpectedEOF},
+		{"no valid answer", &Option{Long: "force", Default: false}, "maybe\nperhaps\nsure?\n", ErrNoAnswer},
+		{"no valid choice", &Option{Long: "format", Choices: []string{"a"}}, "3\n4\n5\n", ErrNoAnswer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Prompter{Input: strings.NewReader(tt.input), Output: io.Discard}
+			if _, err := p.PromptOption(tt.option); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PromptOption() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrompter_PromptArgument(t *testing.T) {
+	var out bytes.Buffer
+	p := &Prompter{Input: strings.NewReader("https://example.com\n"), Output: &out}
+	got, err := p.PromptArgument(&Argument{Name: "url", Description: "The url to fetch"})
+	if err != nil {
+		t.Fatalf("PromptArgument() error = %v", err)
+	}
+	if got != "https://example.com" {
+		t.Errorf("PromptArgument() = %q, want %q", got, "https://example.com")
+	}
+	if out.String() != "The url to fetch: " {
+		t.Errorf("output = %q, want %q", out.String(), "The url to fetch: ")
+	}
+}
@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Token is a command-line argument together with the place it came from.
+type Token struct {
+	Value  string // The argument
+	Source string // "file:line" if the argument was read from a response file, empty otherwise
+}
+
+// NewTokens returns the arguments as tokens given on the command line.
+func NewTokens(args []string) []Token {
+	tokens := make([]Token, len(args))
+	for i, arg := range args {
+		tokens[i] = Token{Value: arg}
+	}
+	return tokens
+}
+
+// TokenValues returns the arguments of tokens.
+func TokenValues(tokens []Token) []string {
+	args := make([]string, len(tokens))
+	for i, token := range tokens {
+		args[i] = token.Value
+	}
+	return args
+}
+
+// ResponseFileExpander expands "@path" arguments into the arguments read from
+// the file at path. Files use shell-like quoting, lines starting with "#" are
+// comments, and files may include further response files. Relative paths in a
+// response file are resolved against the directory of that file.
+type ResponseFileExpander struct {
+	ReadFile func(path string) ([]byte, error) // Reads a response file (defaults to os.ReadFile)
+}
+
+// Expand returns the arguments with every "@path" argument replaced by the
+// contents of the file. An argument of "@@value" is kept as the literal
+// "@value" and no expansion takes place after a "--" argument.
+func (e *ResponseFileExpander) Expand(args []string) ([]Token, error) {
+	return e.expand(NewTokens(args), "", nil)
+}
+
+// expand replaces the response file references in tokens. dir is the directory
+// relative paths are resolved against and stack holds the files currently being
+// expanded, which is used to detect include cycles.
+func (e *ResponseFileExpander) expand(tokens []Token, dir string, stack []string) ([]Token, error) {
+	var result []Token
+	for i, token := range tokens {
+		switch {
+		case token.Value == "--":
+			return append(result, tokens[i:]...), nil
+		case strings.HasPrefix(token.Value, "@@"):
+			token.Value = token.Value[1:]
+			result = append(result, token)
+		case len(token.Value) > 1 && token.Value[0] == '@':
+			expanded, err := e.expandFile(token, dir, stack)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, expanded...)
+			if n := len(result); n > 0 && result[n-1].Value == "--" {
+				return append(result, tokens[i+1:]...), nil
+			}
+		default:
+			result = append(result, token)
+		}
+	}
+	return result, nil
+}
+
+// expandFile reads and tokenizes the response file referenced by token and
+// expands any response files it includes.
+func (e *ResponseFileExpander) expandFile(token Token, dir string, stack []string) ([]Token, error) {
+	path := token.Value[1:]
+	if !filepath.IsAbs(path) && dir != "" {
+		path = filepath.Join(dir, path)
+	}
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		absolute = path
+	}
+
+	for _, open := range stack {
+		if open == absolute {
+			return nil, e.error(token, fmt.Sprintf("response file cycle: %s -> %s", strings.Join(stack, " -> "), absolute), nil)
+		}
+	}
+
+	readFile := e.ReadFile
+	if readFile == nil {
+		readFile = os.ReadFile
+	}
+	data, err := readFile(path)
+	if err != nil {
+		return nil, e.error(token, fmt.Sprintf("cannot read response file: %v", err), err)
+	}
+
+	tokens, err := tokenize(string(data), path)
+	if err != nil {
+		return nil, e.error(token, err.Error(), err)
+	}
+	return e.expand(tokens, filepath.Dir(path), append(stack, absolute))
+}
+
+// error returns a UsageError for a problem with the response file referenced by token.
+func (e *ResponseFileExpander) error(token Token, message string, err error) *UsageError {
+	return &UsageError{
+		Kind:     ErrorKindResponseFile,
+		Argument: token.Value,
+		Source:   token.Source,
+		Message:  message,
+		Err:      err,
+	}
+}
+
+// tokenize splits the contents of a response file into arguments using shell-like
+// rules: whitespace separates arguments, single quotes preserve everything up to
+// the closing quote, double quotes allow backslash escapes of '"' and '\', a
+// backslash outside quotes escapes the next character, and a "#" at the start
+// of an argument starts a comment that runs to the end of the line.
+func tokenize(data string, path string) ([]Token, error) {
+	var tokens []Token
+	var current strings.Builder
+	inToken := false
+	line, tokenLine := 1, 1
+	var quote rune
+	quoteLine := 0
+	escaped := false
+	comment := false
+
+	emit := func() {
+		if inToken {
+			tokens = append(tokens, Token{Value: current.String(), Source: fmt.Sprintf("%s:%d", path, tokenLine)})
+			current.Reset()
+			inToken = false
+		}
+	}
+	start := func() {
+		if !inToken {
+			inToken = true
+			tokenLine = line
+		}
+	}
+
+	for _, r := range data {
+		if r == '\n' {
+			line++
+		}
+		switch {
+		case comment:
+			if r == '\n' {
+				comment = false
+			}
+		case escaped:
+			// A backslash before a newline continues the line, inside double quotes
+			// only '"' and '\' can be escaped and other backslashes are kept
+			escaped = false
+			if r == '\n' {
+				break
+			}
+			if quote == '"' && r != '"' && r != '\\' {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			start()
+			escaped = true
+		case r == '\'' || r == '"':
+			start()
+			quote = r
+			quoteLine = line
+		case r == '#' && !inToken:
+			comment = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			emit()
+		default:
+			start()
+			current.WriteRune(r)
+		}
+	}
+
+	if quote != 0 {
+		kind := "double"
+		if quote == '\'' {
+			kind = "single"
+		}
+		return nil, fmt.Errorf("%s:%d: %w", path, quoteLine, &quoteError{kind: kind})
+	}
+	emit()
+	return tokens, nil
+}
+
+// quoteError reports a quote without a closing quote.
+type quoteError struct {
+	kind string // "single" or "double"
+}
+
+func (e *quoteError) Error() string {
+	return fmt.Sprintf("unterminated %s quote", e.kind)
+}
+
+// SourceOf returns the source of the first token that sets the option with the
+// given name (without dashes), or an empty string if the option was not read
+// from a response file.
+func SourceOf(tokens []Token, option string) string {
+	for _, token := range tokens {
+		if token.Value == "--" {
+			break
+		}
+		if !strings.HasPrefix(token.Value, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(token.Value, "-"), "=")
+		if name == option {
+			return token.Source
+		}
+	}
+	return ""
+}
@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeResponseFile writes content to name inside dir and returns the path.
+func writeResponseFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// tokenValues returns the values of the tokens.
+func tokenValues(tokens []Token) []string {
+	values := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		values = append(values, token.Value)
+	}
+	return values
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"whitespace", "--verbose  --timeout\t5\n\nfile", []string{"--verbose", "--timeout", "5", "file"}},
+		{"single quotes", `--name 'John "JJ" Doe'`, []string{"--name", `John "JJ" Doe`}},
+		{"double quotes", `--name "John \"JJ\" Doe" "a\nb"`, []string{"--name", `John "JJ" Doe`, `a\nb`}},
+		{"adjacent quotes", `--name=' a'"b "c`, []string{"--name= ab c"}},
+		{"escapes", `a\ b c\\d`, []string{"a b", `c\d`}},
+		{"line continuation", "--name long\\\nvalue", []string{"--name", "longvalue"}},
+		{"comments", "# header\n--verbose # trailing\nfile#1", []string{"--verbose", "file#1"}},
+		{"empty quotes", `--name ""`, []string{"--name", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenize(tt.data, "args.rsp")
+			if err != nil {
+				t.Fatalf("tokenize() error = %v", err)
+			}
+			if got := tokenValues(tokens); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize_Sources(t *testing.T) {
+	tokens, err := tokenize("--verbose\n\n--name 'multi\nline' file", "args.rsp")
+	if err != nil {
+		t.Fatalf("tokenize() error = %v", err)
+	}
+	want := []string{"args.rsp:1", "args.rsp:3", "args.rsp:3", "args.rsp:4"}
+	for i, token := range tokens {
+		if token.Source != want[i] {
+			t.Errorf("token %q source = %q, want %q", token.Value, token.Source, want[i])
+		}
+	}
+}
+
+func TestTokenize_UnterminatedQuote(t *testing.T) {
+	_, err := tokenize("--verbose\n--name 'John", "args.rsp")
+	if err == nil || err.Error() != "args.rsp:2: unterminated single quote" {
+		t.Errorf("tokenize() error = %v, want unterminated single quote on line 2", err)
+	}
+}
+
+func TestResponseFileExpander_Expand(t *testing.T) {
+	dir := t.TempDir()
+	writeResponseFile(t, dir, "nested/inner.rsp", "--timeout 5")
+	base := writeResponseFile(t, dir, "base.rsp", "--verbose\n@nested/inner.rsp\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"no response files", []string{"--verbose", "file"}, []string{"--verbose", "file"}},
+		{"nested include", []string{"@" + base, "file"}, []string{"--verbose", "--timeout", "5", "file"}},
+		{"escaped at", []string{"@@user", "@"}, []string{"@user", "@"}},
+		{"after terminator", []string{"--", "@" + base}, []string{"--", "@" + base}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expander := &ResponseFileExpander{}
+			tokens, err := expander.Expand(tt.args)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if got := tokenValues(tokens); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseFileExpander_Errors(t *testing.T) {
+	dir := t.TempDir()
+	a := writeResponseFile(t, dir, "a.rsp", "--verbose\n@b.rsp")
+	writeResponseFile(t, dir, "b.rsp", "@a.rsp")
+	bad := writeResponseFile(t, dir, "bad.rsp", "--name \"unterminated")
+
+	tests := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{"cycle", []string{"@" + a}, "response file cycle"},
+		{"missing file", []string{"@" + filepath.Join(dir, "missing.rsp")}, "cannot read response file"},
+		{"unterminated quote", []string{"@" + bad}, "bad.rsp:1: unterminated double quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expander := &ResponseFileExpander{}
+			_, err := expander.Expand(tt.args)
+
+			var usageErr *UsageError
+			if !errors.As(err, &usageErr) || usageErr.Kind != ErrorKindResponseFile {
+				t.Fatalf("Expand() error = %v, want a response file error", err)
+			}
+			if !strings.Contains(usageErr.Error(), tt.message) {
+				t.Errorf("Expand() error = %q, want it to contain %q", usageErr.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestSourceOf(t *testing.T) {
+	tokens := []Token{
+		{Value: "--verbose"},
+		{Value: "--timeout=5", Source: "args.rsp:2"},
+		{Value: "--", Source: "args.rsp:3"},
+		{Value: "--name", Source: "args.rsp:4"},
+	}
+
+	tests := []struct {
+		option string
+		want   string
+	}{
+		{"verbose", ""},
+		{"timeout", "args.rsp:2"},
+		{"name", ""},
+		{"missing", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.option, func(t *testing.T) {
+			if got := SourceOf(tokens, tt.option); got != tt.want {
+				t.Errorf("SourceOf(%q) = %q, want %q", tt.option, got, tt.want)
+			}
+		})
+	}
+}
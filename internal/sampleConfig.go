@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConfigFormat selects the file format of a sample configuration.
+type ConfigFormat string
+
+const (
+	ConfigFormatINI  ConfigFormat = "ini"  // An INI file as read by the configuration file support
+	ConfigFormatYAML ConfigFormat = "yaml" // A YAML document
+	ConfigFormatTOML ConfigFormat = "toml" // A TOML document
+	ConfigFormatJSON ConfigFormat = "json" // A JSON object, without comments as JSON has none
+)
+
+// ParseConfigFormat returns the format with the given name, ignoring case,
+// where "yml" is accepted for YAML.
+func ParseConfigFormat(name string) (ConfigFormat, error) {
+	switch format := ConfigFormat(strings.ToLower(name)); format {
+	case ConfigFormatINI, ConfigFormatYAML, ConfigFormatTOML, ConfigFormatJSON:
+		return format, nil
+	case "yml":
+		return ConfigFormatYAML, nil
+	}
+	return "", fmt.Errorf("must be one of ini, yaml, toml or json")
+}
+
+// SampleConfig returns a sample configuration file in the given format with a
+// setting for each option, grouped as in the usage output. Hidden, secret and
+// deprecated options, options of hidden or deprecated groups and the options
+// in exclude are left out. Each group starts with a comment holding its name
+// and description, and each setting is preceded by comments describing the
+// option, its type, default value, choices and environment variable. Settings
+// are set to the default value of their option, settings of options without a
+// default are commented out. JSON has no comments, so the JSON sample only
+// holds the settings, with null for options without a default.
+func SampleConfig(c *Configuration, format ConfigFormat, exclude map[*Option]bool) (string, error) {
+	if _, err := ParseConfigFormat(string(format)); err != nil {
+		return "", fmt.Errorf("invalid config format %q: %w", format, err)
+	}
+	var groups []*Group
+	for _, group := range c.SortedGroups() {
+		if group.Hidden || group.Deprecated != "" {
+			continue
+		}
+		sample := &Group{Name: group.Name, Description: group.Description}
+		for _, option := range group.Options {
+			if !option.Hidden && !option.Secret && !option.IsDeprecated() && !exclude[option] {
+				sample.Options = append(sample.Options, option)
+			}
+		}
+		if len(sample.Options) > 0 {
+			groups = append(groups, sample)
+		}
+	}
+
+	if format == ConfigFormatJSON {
+		return sampleJSON(groups), nil
+	}
+	var b strings.Builder
+	if c.ApplicationName != "" {
+		fmt.Fprintf(&b, "# Configuration for %s\n", c.ApplicationName)
+	}
+	for _, group := range groups {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# %s\n", group.Name)
+		if group.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", group.Description)
+		}
+		for _, option := range group.Options {
+			b.WriteString("\n")
+			for _, comment := range sampleComments(option) {
+				fmt.Fprintf(&b, "# %s\n", comment)
+			}
+			value, ok := sampleValue(option, format)
+			setting := fmt.Sprintf("%s = %s", option.Name(), value)
+			if format == ConfigFormatYAML {
+				setting = fmt.Sprintf("%s: %s", option.Name(), value)
+			}
+			if !ok {
+				setting = "# " + setting
+			}
+			b.WriteString(strings.TrimSpace(setting) + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// sampleComments returns the comments describing option in a sample
+// configuration.
+func sampleComments(option *Option) []string {
+	var comments []string
+	if option.Description != "" {
+		description := option.Description
+		if option.Required {
+			description += " (required)"
+		}
+		comments = append(comments, description)
+	}
+	details := []string{"Type: " + sampleType(option)}
+	if text := sampleDefault(option); text != "" {
+		details = append(details, "Default: "+text)
+	}
+	comments = append(comments, strings.Join(details, ", "))
+	if len(option.Choices) > 0 {
+		comments = append(comments, "Allowed values: "+strings.Join(option.Choices, ", "))
+	}
+	if option.Env != "" {
+		comments = append(comments, "Environment variable: "+option.Env)
+	}
+	return comments
+}
+
+// sampleType returns the name of the value type of option, "string" when it
+// cannot be derived.
+func sampleType(option *Option) string {
+	if name := option.TypeName(); name != "" {
+		return name
+	}
+	return "string"
+}
+
+// sampleDefault returns the default value of option as it is given on the
+// command line, or an empty string if it has none.
+func sampleDefault(option *Option) string {
+	if option.Default == nil {
+		return ""
+	}
+	return fmt.Sprint(option.Default)
+}
+
+// sampleValue returns the default value of option written in format, and
+// whether the option has a default. Options without one get an empty value.
+func sampleValue(option *Option, format ConfigFormat) (string, bool) {
+	text := sampleDefault(option)
+	typ := sampleType(option)
+	if format == ConfigFormatINI {
+		return text, text != ""
+	}
+
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		var items []string
+		if text != "" {
+			for _, item := range strings.Split(text, ",") {
+				items = append(items, sampleScalar(typ[2:], item, format))
+			}
+		}
+		return "[" + strings.Join(items, ", ") + "]", text != ""
+	case strings.HasPrefix(typ, "map["):
+		_, valueType, _ := strings.Cut(strings.TrimPrefix(typ, "map["), "]")
+		separator := ": "
+		if format == ConfigFormatTOML {
+			separator = " = "
+		}
+		var pairs []string
+		if text != "" {
+			for _, pair := range strings.Split(text, ",") {
+				key, value, _ := strings.Cut(pair, "=")
+				pairs = append(pairs, sampleScalar("string", key, format)+separator+sampleScalar(valueType, value, format))
+			}
+		}
+		return "{" + strings.Join(pairs, ", ") + "}", text != ""
+	}
+	if text == "" {
+		return sampleScalar("string", "", format), false
+	}
+	return sampleScalar(typ, text, format), true
+}
+
+// sampleScalar returns a single value of the given type written in format:
+// booleans and numbers as they are and anything else as a quoted string.
+func sampleScalar(typ string, text string, format ConfigFormat) string {
+	switch typ {
+	case "bool":
+		if b, err := strconv.ParseBool(text); err == nil {
+			return strconv.FormatBool(b)
+		}
+	case "int", "uint":
+		if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return text
+		}
+		if _, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return text
+		}
+	case "float":
+		// Infinity and NaN are written differently in each format
+		if _, err := strconv.ParseFloat(text, 64); err == nil && !strings.ContainsAny(text, "iInN") {
+			if format == ConfigFormatTOML && !strings.ContainsAny(text, ".eE") {
+				return text + ".0"
+			}
+			return text
+		}
+	}
+	if format == ConfigFormatJSON {
+		data, _ := json.Marshal(text)
+		return string(data)
+	}
+	return strconv.Quote(text)
+}
+
+// sampleJSON returns the settings of the options of groups as a JSON object.
+func sampleJSON(groups []*Group) string {
+	var lines []string
+	for _, group := range groups {
+		for _, option := range group.Options {
+			value, ok := sampleValue(option, ConfigFormatJSON)
+			if !ok {
+				value = "null"
+			}
+			lines = append(lines, fmt.Sprintf("  %s: %s", sampleScalar("string", option.Name(), ConfigFormatJSON), value))
+		}
+	}
+	if len(lines) == 0 {
+		return "{}\n"
+	}
+	return "{\n" + strings.Join(lines, ",\n") + "\n}\n"
+}
+
+// ConfigFormatFlag is a flag.Value for the option that generates a sample
+// configuration. It behaves like a boolean flag, so "--generate-config"
+// requests the INI format, and also accepts a format such as
+// "--generate-config=yaml".
+type ConfigFormatFlag struct {
+	Requested bool         // Whether the option was used
+	Format    ConfigFormat // The requested format
+}
+
+// String returns the requested format, or an empty string if none was requested.
+func (f *ConfigFormatFlag) String() string {
+	if f == nil || !f.Requested {
+		return ""
+	}
+	return string(f.Format)
+}
+
+// Set records the request. Boolean values request the INI format.
+func (f *ConfigFormatFlag) Set(value string) error {
+	switch strings.ToLower(value) {
+	case "true", "1":
+		f.Requested, f.Format = true, ConfigFormatINI
+	case "false", "0":
+		f.Requested, f.Format = false, ""
+	default:
+		format, err := ParseConfigFormat(value)
+		if err != nil {
+			return err
+		}
+		f.Requested, f.Format = true, format
+	}
+	return nil
+}
+
+// IsBoolFlag allows the option to be used without a value.
+func (f *ConfigFormatFlag) IsBoolFlag() bool {
+	return true
+}
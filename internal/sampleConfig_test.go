@@ -0,0 +1,192 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func sampleConfiguration() (*Configuration, *Option) {
+	excluded := &Option{Long: "version", Default: false, Description: "Show version"}
+	return &Configuration{
+		ApplicationName: "app",
+		Groups: map[string]*Group{
+			"Default": {Priority: 0, Name: "Default", Description: "Default Options", Options: []*Option{
+				{Long: "tag", Type: "[]string", Default: "a,b", Description: "Tags"},
+				{Short: "m", Long: "mode", Default: "fast", Description: "Mode", Choices: []string{"fast", "slow"}, Required: true},
+				{Long: "name", Default: "", Description: "Name", Env: "APP_NAME"},
+				{Long: "token", Default: "", Description: "Token", Secret: true},
+				{Long: "debug", Default: false, Description: "Debug", Hidden: true},
+				{Long: "old", Default: 1, Description: "Old", Deprecated: "no longer used"},
+				excluded,
+			}},
+			"Network": {Priority: 1, Name: "Network", Options: []*Option{
+				{Long: "retries", Default: 3, Description: "Retries"},
+				{Long: "ratio", Default: 1.0, Description: "Ratio"},
+				{Long: "headers", Type: "map[string]string", Default: "a=1", Description: "Headers"},
+			}},
+			"Legacy": {Priority: 2, Name: "Legacy", Deprecated: "no longer used", Options: []*Option{
+				{Long: "legacy", Default: true, Description: "Legacy"},
+			}},
+		},
+	}, excluded
+}
+
+func TestSampleConfig(t *testing.T) {
+	tests := []struct {
+		format ConfigFormat
+		want   string
+	}{
+		{ConfigFormatINI, `# Configuration for app
+
+# Default
+# Default Options
+
+# Tags
+# Type: []string, Default: a,b
+tag = a,b
+
+# Mode (required)
+# Type: string, Default: fast
+# Allowed values: fast, slow
+mode = fast
+
+# Name
+# Type: string
+# Environment variable: APP_NAME
+# name =
+
+# Network
+
+# Retries
+# Type: int, Default: 3
+retries = 3
+
+# Ratio
+# Type: float, Default: 1
+ratio = 1
+
+# Headers
+# Type: map[string]string, Default: a=1
+headers = a=1
+`},
+		{ConfigFormatYAML, `# Configuration for app
+
+# Default
+# Default Options
+
+# Tags
+# Type: []string, Default: a,b
+tag: ["a", "b"]
+
+# Mode (required)
+# Type: string, Default: fast
+# Allowed values: fast, slow
+mode: "fast"
+
+# Name
+# Type: string
+# Environment variable: APP_NAME
+# name: ""
+
+# Network
+
+# Retries
+# Type: int, Default: 3
+retries: 3
+
+# Ratio
+# Type: float, Default: 1
+ratio: 1
+
+# Headers
+# Type: map[string]string, Default: a=1
+headers: {"a": "1"}
+`},
+		{ConfigFormatJSON, `{
+  "tag": ["a", "b"],
+  "mode": "fast",
+  "name": null,
+  "retries": 3,
+  "ratio": 1,
+  "headers": {"a": "1"}
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			c, excluded := sampleConfiguration()
+			got, err := SampleConfig(c, tt.format, map[*Option]bool{excluded: true})
+			if err != nil {
+				t.Fatalf("SampleConfig() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SampleConfig() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleConfig_TOML(t *testing.T) {
+	c, _ := sampleConfiguration()
+	got, err := SampleConfig(c, ConfigFormatTOML, nil)
+	if err != nil {
+		t.Fatalf("SampleConfig() error = %v", err)
+	}
+	for _, want := range []string{"\ntag = [\"a\", \"b\"]\n", "\nmode = \"fast\"\n", "\n# name = \"\"\n", "\nratio = 1.0\n", "\nheaders = {\"a\" = \"1\"}\n", "\nversion = false\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SampleConfig() = %s, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestSampleConfig_JSONIsValid(t *testing.T) {
+	c, _ := sampleConfiguration()
+	got, err := SampleConfig(c, ConfigFormatJSON, nil)
+	if err != nil {
+		t.Fatalf("SampleConfig() error = %v", err)
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &settings); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(settings) != 7 || settings["retries"] != 3.0 || settings["name"] != nil {
+		t.Errorf("settings = %v", settings)
+	}
+}
+
+func TestSampleConfig_InvalidFormat(t *testing.T) {
+	_, err := SampleConfig(&Configuration{}, "xml", nil)
+	if err == nil || err.Error() != `invalid config format "xml": must be one of ini, yaml, toml or json` {
+		t.Errorf("SampleConfig() error = %v", err)
+	}
+}
+
+func TestConfigFormatFlag_Set(t *testing.T) {
+	tests := []struct {
+		value     string
+		requested bool
+		format    ConfigFormat
+		wantErr   bool
+	}{
+		{"true", true, ConfigFormatINI, false},
+		{"false", false, "", false},
+		{"YAML", true, ConfigFormatYAML, false},
+		{"yml", true, ConfigFormatYAML, false},
+		{"toml", true, ConfigFormatTOML, false},
+		{"json", true, ConfigFormatJSON, false},
+		{"xml", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var f ConfigFormatFlag
+			err := f.Set(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if f.Requested != tt.requested || f.Format != tt.format {
+				t.Errorf("Set() = %v %q, want %v %q", f.Requested, f.Format, tt.requested, tt.format)
+			}
+		})
+	}
+}
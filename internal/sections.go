@@ -0,0 +1,46 @@
+package internal
+
+import "strings"
+
+// SectionPosition determines where a custom Section is rendered relative to
+// the built-in sections of the usage output.
+type SectionPosition int
+
+const (
+	SectionTop           SectionPosition = iota // After the usage line, before the version information
+	SectionBeforeOptions                        // After the description, before the options
+	SectionBottom                               // After the arguments and examples, as a footer
+)
+
+// String returns the name of the position as used in JSON output.
+func (p SectionPosition) String() string {
+	switch p {
+	case SectionTop:
+		return "top"
+	case SectionBeforeOptions:
+		return "before_options"
+	case SectionBottom:
+		return "bottom"
+	}
+	return "unknown"
+}
+
+// Example represents a sample invocation of the application shown in the
+// "Examples" section of the usage output.
+type Example struct {
+	Command     string // Command line of the example, e.g. "myapp -t 5 http://example.com"
+	Explanation string // Text explaining what the example does
+}
+
+// Section represents a free-form block of text in the usage output such as
+// "See also", environment notes, bug-report URLs or copyright information.
+type Section struct {
+	Title    string          // Title shown as the section header
+	Body     string          // Text of the section, line breaks are preserved
+	Position SectionPosition // Where the section is rendered
+}
+
+// Lines returns the lines of the section body with trailing blank lines removed.
+func (s *Section) Lines() []string {
+	return strings.Split(strings.TrimRight(s.Body, "\n"), "\n")
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSectionPosition_String(t *testing.T) {
+	tests := []struct {
+		position SectionPosition
+		want     string
+	}{
+		{SectionTop, "top"},
+		{SectionBeforeOptions, "before_options"},
+		{SectionBottom, "bottom"},
+		{SectionPosition(42), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.position.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSection_Lines(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"single line", "https://example.com/bugs", []string{"https://example.com/bugs"}},
+		{"multiple lines", "line one\nline two", []string{"line one", "line two"}},
+		{"trailing newlines", "line one\n\n", []string{"line one"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			section := Section{Body: tt.body}
+			if got := section.Lines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
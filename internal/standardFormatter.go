@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 // StandardFormatter implements the Formatter interface using plain text
@@ -17,7 +18,9 @@ type StandardFormatter struct {
 
 // PrintUsage outputs formatted usage information in plain text.
 // It displays application metadata (version, build date, commit hash),
-// description, and options grouped by category.
+// description, options grouped by category in priority order, positional
+// arguments, examples and any custom sections at their configured positions.
+// All columns are aligned within each group.
 // If Output is nil, it defaults to os.Stdout.
 func (f *StandardFormatter) PrintUsage() {
 	if f.Output == nil {
@@ -25,55 +28,150 @@ func (f *StandardFormatter) PrintUsage() {
 	}
 
 	// Print the usage line
-	fmt.Printf("Usage: %s [OPTIONS] [ARGUMENTS]\n\n", f.Configuration.ApplicationName)
+	fmt.Fprintf(f.Output, "Usage: %s\n\n", Synopsis(f.Configuration))
 
-	// Print the description if one is provided
-	if f.Configuration.ApplicationDescription != "" {
-		fmt.Printf("Description: %s\n\n", f.Configuration.ApplicationDescription)
-
-	}
+	// Print any custom sections placed at the top
+	f.printSections(f.Configuration.SectionsAt(SectionTop))
 
 	// Print the version information if it is provided
+	versionInfoPresent := false
 	if f.Configuration.ApplicationVersion != "" {
-		fmt.Printf("Version: %s\n", f.Configuration.ApplicationVersion)
+		fmt.Fprintf(f.Output, "Version: %s\n", f.Configuration.ApplicationVersion)
+		versionInfoPresent = true
 	}
 
 	// Print the build date information if it is provided
 	if f.Configuration.ApplicationBuildDate != "" {
-		fmt.Printf("Date: %s\n", f.Configuration.ApplicationBuildDate)
+		fmt.Fprintf(f.Output, "Date: %s\n", f.Configuration.ApplicationBuildDate)
+		versionInfoPresent = true
 	}
 
 	// Print the commit hash information if it is provided
 	if f.Configuration.ApplicationCommitHash != "" {
-		fmt.Printf("Codebase: %s", f.Configuration.ApplicationCommitHash)
-		if f.Configuration.ApplicationBranch != "" {
-			fmt.Printf(" (%s)", f.Configuration.ApplicationBranch)
+		fmt.Fprintf(f.Output, "Codebase: %s%s\n", f.Configuration.ApplicationCommitHash, codebaseDetails(f.Configuration))
+		versionInfoPresent = true
+	}
+	if versionInfoPresent {
+		fmt.Fprintln(f.Output, "")
+	}
+
+	// Print the description if one is provided, wrapped the same way as the ColorFormatter
+	if f.Configuration.ApplicationDescription != "" {
+		wrappedDescription := wrapText(f.Configuration.ApplicationDescription, 60, len("Description: "))
+		fmt.Fprintf(f.Output, "Description: %s\n", wrappedDescription[0])
+		for _, line := range wrappedDescription[1:] {
+			fmt.Fprintf(f.Output, "  %s\n", line)
 		}
-		fmt.Println("")
+		fmt.Fprintln(f.Output, "")
 	}
-	fmt.Println("")
 
-	fmt.Println("Options:")
-	for _, group := range f.Configuration.Groups {
-		if len(group.Options) == 0 {
+	// Print any custom sections placed before the options
+	f.printSections(f.Configuration.SectionsAt(SectionBeforeOptions))
+
+	fmt.Fprintln(f.Output, "Options:")
+	for _, group := range f.Configuration.SortedGroups() {
+		options := group.VisibleOptions(f.Configuration.ShowAll)
+		if len(options) == 0 {
 			continue
 		}
-		fmt.Printf("  %s: %s\n", group.Name, group.Description)
-		for _, option := range group.Options {
-			fmt.Printf("    -%s, --%s\t\t%s\n", option.Short, option.Long, option.Description)
+		fmt.Fprintf(f.Output, "  %s: %s\n", group.Name, group.Description)
+
+		hasShort, nameWidth, defaultWidth := optionColumnWidths(options)
+
+		for _, option := range options {
+			line := fmt.Sprintf("    %-*s  %-*s  %s", nameWidth, optionNames(option, hasShort), defaultWidth, optionDefault(option), option.HelpDescription())
+			fmt.Fprintln(f.Output, strings.TrimRight(line, " "))
+		}
+		fmt.Fprintln(f.Output, "")
+	}
+
+	// Print the positional arguments ordered by position
+	arguments := f.Configuration.SortedArguments()
+	if len(arguments) > 0 {
+		fmt.Fprintln(f.Output, "Arguments:")
+		nameWidth := argumentNameWidth(arguments)
+		for _, argument := range arguments {
+			line := fmt.Sprintf("    %-*s  %s", nameWidth, argument.Name, argument.HelpDescription())
+			fmt.Fprintln(f.Output, strings.TrimRight(line, " "))
+		}
+	}
+
+	// Print the examples and footer sections
+	examples := f.Configuration.Examples
+	footers := f.Configuration.SectionsAt(SectionBottom)
+	if len(arguments) > 0 && (len(examples) > 0 || len(footers) > 0) {
+		fmt.Fprintln(f.Output, "")
+	}
+	if len(examples) > 0 {
+		fmt.Fprintln(f.Output, "Examples:")
+		for _, example := range examples {
+			fmt.Fprintf(f.Output, "    %s\n", example.Command)
+			if example.Explanation != "" {
+				fmt.Fprintf(f.Output, "        %s\n", example.Explanation)
+			}
+		}
+		fmt.Fprintln(f.Output, "")
+	}
+	f.printSections(footers)
+}
+
+// printSections outputs custom sections with their title as a header and the
+// body indented below it, each followed by a blank line.
+func (f *StandardFormatter) printSections(sections []*Section) {
+	for _, section := range sections {
+		fmt.Fprintf(f.Output, "%s:\n", section.Title)
+		for _, line := range section.Lines() {
+			fmt.Fprintln(f.Output, strings.TrimRight("  "+line, " "))
 		}
-		fmt.Println("")
+		fmt.Fprintln(f.Output, "")
 	}
 }
 
-// PrintError outputs the error message followed by the usage information.
-// If Error is nil, it defaults to os.Stderr. The error message is displayed
-// before the usage information.
+// PrintError outputs the error message to the error writer, followed by the
+// command line with the offending token marked if the error refers to one.
+// Each error of an ErrorList is printed in turn. By default the errors are
+// followed by any suggestions, the usage line and a hint to run --help. With
+// ErrorModeFull the errors are followed by the complete usage information
+// instead. Nothing is printed if err is nil or an empty ErrorList. If Error
+// is nil, it defaults to os.Stderr.
 func (f *StandardFormatter) PrintError(err error) {
 	if f.Error == nil {
 		f.Error = os.Stderr
 	}
 
-	fmt.Fprintln(f.Error, "Error: ", err)
-	f.PrintUsage()
+	usageErrs := UsageErrors(err)
+	if len(usageErrs) == 0 {
+		return
+	}
+	full := f.Configuration.ErrorMode == ErrorModeFull
+	for _, usageErr := range usageErrs {
+		if full {
+			fmt.Fprintln(f.Error, "Error: ", usageErr)
+		} else {
+			fmt.Fprintf(f.Error, "Error: %s\n", usageErr.Summary())
+		}
+		for _, line := range usageErr.Highlight(f.Configuration.ApplicationName) {
+			fmt.Fprintf(f.Error, "  %s\n", line)
+		}
+		if hint := usageErr.SuggestionHint(); hint != "" && !full {
+			fmt.Fprintln(f.Error, capitalize(hint))
+		}
+	}
+
+	if full {
+		f.PrintUsage()
+		return
+	}
+	fmt.Fprintf(f.Error, "\nUsage: %s\n", Synopsis(f.Configuration))
+	fmt.Fprintln(f.Error, HelpHint(f.Configuration))
+}
+
+// PrintWarning outputs a warning message, such as the use of a deprecated
+// option, to the error writer. If Error is nil, it defaults to os.Stderr.
+func (f *StandardFormatter) PrintWarning(message string) {
+	if f.Error == nil {
+		f.Error = os.Stderr
+	}
+
+	fmt.Fprintf(f.Error, "Warning: %s\n", message)
 }
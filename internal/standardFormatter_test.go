@@ -3,14 +3,10 @@ package internal
 import (
 	"bytes"
 	"errors"
+	"strings"
 	"testing"
 )
 
-// NOTE: StandardFormatter currently has a bug where it uses fmt.Printf instead
-// of fmt.Fprintf(f.Output, ...), so it always writes to stdout regardless of
-// the configured Output writer. These tests verify the current behavior.
-// This should be fixed in a future PR.
-
 func TestStandardFormatter_Creation(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -66,7 +62,6 @@ func TestStandardFormatter_PrintError(t *testing.T) {
 				Configuration: config,
 			}
 
-			// PrintError writes to f.Error which IS respected
 			formatter.PrintError(tt.err)
 			output := buf.String()
 
@@ -77,3 +72,298 @@ func TestStandardFormatter_PrintError(t *testing.T) {
 		})
 	}
 }
+
+func TestStandardFormatter_PrintUsage(t *testing.T) {
+	config := &Configuration{
+		ApplicationName:        "myapp",
+		ApplicationVersion:     "1.0.0",
+		ApplicationDescription: "A test application",
+		Groups: map[string]*Group{
+			"Default": {
+				Priority:    0,
+				Name:        "Default",
+				Description: "Default Options",
+				Options: []*Option{
+					{Short: "o", Long: "output", Default: "", Description: "Output filename"},
+					{Long: "dry-run", Default: false, Description: "Only print actions"},
+				},
+				Arguments: []*Argument{
+					{Position: 1, Name: "url", Description: "The url to fetch"},
+				},
+			},
+			"Request": {
+				Priority:    1,
+				Name:        "Request",
+				Description: "Request Options",
+				Options: []*Option{
+					{Short: "t", Long: "timeout", Default: 10, Description: "Timeout in seconds"},
+					{Short: "v", Default: false, Description: "Verbose"},
+				},
+			},
+		},
+	}
+
+	want := `Usage: myapp [OPTIONS] [ARGUMENTS]
+
+Version: 1.0.0
+
+Description: A test application
+
+Options:
+  Default: Default Options
+    -o, --output   -      Output filename
+        --dry-run  false  Only print actions
+
+  Request: Request Options
+    -t, --timeout  10     Timeout in seconds
+    -v             false  Verbose
+
+Arguments:
+    url  The url to fetch
+`
+
+	for run := 0; run < 5; run++ {
+		var buf bytes.Buffer
+		formatter := &StandardFormatter{Output: &buf, Configuration: config}
+		formatter.PrintUsage()
+		if got := buf.String(); got != want {
+			t.Fatalf("PrintUsage() output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+		}
+	}
+}
+
+func TestStandardFormatter_WritersAreSeparate(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+	formatter := &StandardFormatter{
+		Output: &outBuf,
+		Error:  &errBuf,
+		Configuration: &Configuration{
+			ApplicationName: "testapp",
+			Groups:          map[string]*Group{},
+			ErrorMode:       ErrorModeFull,
+		},
+	}
+
+	formatter.PrintError(errors.New("bad flag"))
+
+	if !strings.Contains(errBuf.String(), "bad flag") {
+		t.Errorf("PrintError() error writer = %q, want it to contain the error", errBuf.String())
+	}
+	if strings.Contains(outBuf.String(), "bad flag") {
+		t.Errorf("PrintError() wrote the error to the output writer")
+	}
+	if !strings.Contains(outBuf.String(), "Usage: testapp") {
+		t.Errorf("PrintError() output writer = %q, want usage", outBuf.String())
+	}
+}
+
+func TestStandardFormatter_PrintErrorConcise(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "with suggestions",
+			err: &UsageError{
+				Kind:        ErrorKindUnknownOption,
+				Option:      "tiemout",
+				Message:     "flag provided but not defined: -tiemout",
+				Suggestions: []string{"--timeout"},
+			},
+			want: "Error: flag provided but not defined: -tiemout\n" +
+				"Did you mean --timeout?\n" +
+				"\n" +
+				"Usage: testapp [OPTIONS] [ARGUMENTS]\n" +
+				"Run 'testapp --help' for more information.\n",
+		},
+		{
+			name: "several errors",
+			err: ErrorList{
+				&UsageError{
+					Message: `invalid value "x" for flag -timeout: parse error`,
+					Args:    []string{"--timeout", "x"},
+					Err:     &InvalidValueError{Location: Location{Token: "x", Index: 1}},
+				},
+				&UsageError{Message: "missing required option --name"},
+			},
+			want: "Error: invalid value \"x\" for flag -timeout: parse error\n" +
+				"  testapp --timeout x\n" +
+				"                    ^\n" +
+				"Error: missing required option --name\n" +
+				"\n" +
+				"Usage: testapp [OPTIONS] [ARGUMENTS]\n" +
+				"Run 'testapp --help' for more information.\n",
+		},
+		{
+			name: "plain error",
+			err:  errors.New("bad flag"),
+			want: "Error: bad flag\n" +
+				"\n" +
+				"Usage: testapp [OPTIONS] [ARGUMENTS]\n" +
+				"Run 'testapp --help' for more information.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var outBuf, errBuf bytes.Buffer
+			formatter := &StandardFormatter{
+				Output: &outBuf,
+				Error:  &errBuf,
+				Configuration: &Configuration{
+					ApplicationName: "testapp",
+					Groups: map[string]*Group{
+						"Default": {Name: "Default", Options: []*Option{{Long: "timeout", Default: 10}}},
+					},
+				},
+			}
+			formatter.PrintError(tt.err)
+
+			if got := errBuf.String(); got != tt.want {
+				t.Errorf("PrintError() =\n%s\nwant:\n%s", got, tt.want)
+			}
+			if outBuf.Len() != 0 {
+				t.Errorf("PrintError() wrote the usage to the output writer: %q", outBuf.String())
+			}
+		})
+	}
+}
+
+func TestStandardFormatter_ExamplesAndSections(t *testing.T) {
+	config := &Configuration{
+		ApplicationName: "myapp",
+		Groups: map[string]*Group{
+			"Default": {
+				Name:      "Default",
+				Arguments: []*Argument{{Position: 1, Name: "url", Description: "The url"}},
+			},
+		},
+		Examples: []*Example{
+			{Command: "myapp https://example.com", Explanation: "Fetch a page"},
+			{Command: "myapp --help"},
+		},
+		Sections: []*Section{
+			{Title: "Notice", Body: "Beta software", Position: SectionTop},
+			{Title: "Environment", Body: "MYAPP_HOME  Home directory", Position: SectionBeforeOptions},
+			{Title: "Bugs", Body: "https://example.com/bugs", Position: SectionBottom},
+			{Title: "Copyright", Body: "(c) Example\nAll rights reserved", Position: SectionBottom},
+		},
+	}
+
+	want := `Usage: myapp [OPTIONS] [ARGUMENTS]
+
+Notice:
+  Beta software
+
+Environment:
+  MYAPP_HOME  Home directory
+
+Options:
+Arguments:
+    url  The url
+
+Examples:
+    myapp https://example.com
+        Fetch a page
+    myapp --help
+
+Bugs:
+  https://example.com/bugs
+
+Copyright:
+  (c) Example
+  All rights reserved
+
+`
+
+	var buf bytes.Buffer
+	formatter := &StandardFormatter{Output: &buf, Configuration: config}
+	formatter.PrintUsage()
+	if got := buf.String(); got != want {
+		t.Errorf("PrintUsage() output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStandardFormatter_Visibility(t *testing.T) {
+	config := &Configuration{
+		ApplicationName: "myapp",
+		Groups: map[string]*Group{
+			"Default": {
+				Name: "Default",
+				Options: []*Option{
+					{Long: "verbose", Default: false, Description: "Verbose output"},
+					{Long: "debug-internals", Default: false, Description: "Dump internals", Hidden: true},
+					{Long: "buffer-size", Default: 4096, Description: "Buffer size", Advanced: true},
+					{Long: "out", Default: "", Description: "Output file", Deprecated: "renamed", Replacement: "output"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		showAll bool
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "regular help",
+			showAll: false,
+			want:    []string{"--verbose"},
+			notWant: []string{"--debug-internals", "--buffer-size", "--out"},
+		},
+		{
+			name:    "all help",
+			showAll: true,
+			want:    []string{"--verbose", "--buffer-size", "Output file (deprecated, use --output)"},
+			notWant: []string{"--debug-internals"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.ShowAll = tt.showAll
+			var buf bytes.Buffer
+			formatter := &StandardFormatter{Output: &buf, Configuration: config}
+			formatter.PrintUsage()
+			output := buf.String()
+
+			for _, expected := range tt.want {
+				if !strings.Contains(output, expected) {
+					t.Errorf("PrintUsage() output missing expected substring %q", expected)
+				}
+			}
+			for _, unexpected := range tt.notWant {
+				if strings.Contains(output, unexpected) {
+					t.Errorf("PrintUsage() output contains unexpected substring %q", unexpected)
+				}
+			}
+		})
+	}
+}
+
+func TestStandardFormatter_PrintErrorNone(t *testing.T) {
+	for _, err := range []error{nil, ErrorList{}} {
+		var outBuf, errBuf bytes.Buffer
+		formatter := &StandardFormatter{Output: &outBuf, Error: &errBuf, Configuration: &Configuration{}}
+		formatter.PrintError(err)
+		if outBuf.Len() != 0 || errBuf.Len() != 0 {
+			t.Errorf("PrintError(%#v) wrote %q and %q, want nothing", err, outBuf.String(), errBuf.String())
+		}
+	}
+}
+
+func TestStandardFormatter_PrintWarning(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+	formatter := &StandardFormatter{Output: &outBuf, Error: &errBuf, Configuration: &Configuration{}}
+
+	formatter.PrintWarning("option --out is deprecated")
+
+	if want := "Warning: option --out is deprecated\n"; errBuf.String() != want {
+		t.Errorf("PrintWarning() error writer = %q, want %q", errBuf.String(), want)
+	}
+	if outBuf.Len() != 0 {
+		t.Errorf("PrintWarning() wrote to the output writer: %q", outBuf.String())
+	}
+}
@@ -0,0 +1,107 @@
+package internal
+
+import "sort"
+
+// EditDistance returns the optimal string alignment distance between a and b,
+// the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters needed to turn a into b.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// Suggest returns the candidates that are likely to be what the user meant by
+// input, ordered from the closest match. A candidate matches when its edit
+// distance to input is at most maxDistance (and smaller than the candidate
+// itself, so short names are not suggested for unrelated input), or when it
+// starts with input and input is at least two characters long.
+// No suggestions are returned when maxDistance is less than one.
+func Suggest(input string, candidates []string, maxDistance int) []string {
+	if maxDistance < 1 || input == "" {
+		return nil
+	}
+
+	type match struct {
+		candidate string
+		distance  int
+	}
+	var matches []match
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if candidate == "" || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		distance := EditDistance(input, candidate)
+		prefix := len(input) >= 2 && len(candidate) > len(input) && candidate[:len(input)] == input
+		if (distance <= maxDistance && distance < len(candidate)) || prefix {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].candidate < matches[j].candidate
+	})
+
+	suggestions := make([]string, 0, len(matches))
+	for _, m := range matches {
+		suggestions = append(suggestions, m.candidate)
+	}
+	return suggestions
+}
+
+// OptionNames returns the short and long names of all options that are
+// offered as suggestions, skipping hidden and deprecated options.
+func (c *Configuration) OptionNames() []string {
+	var names []string
+	for _, group := range c.SortedGroups() {
+		if group.Hidden || group.Deprecated != "" {
+			continue
+		}
+		for _, option := range group.Options {
+			if option.Hidden || option.IsDeprecated() {
+				continue
+			}
+			for _, name := range append([]string{option.Short, option.Long}, option.Aliases...) {
+				if name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// minInt returns the smallest of the given values.
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
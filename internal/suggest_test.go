@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"timeout", "timeout", 0},
+		{"timout", "timeout", 1},
+		{"tiemout", "timeout", 1},
+		{"verbose", "", 7},
+		{"output", "outptu", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := EditDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"t", "timeout", "v", "verbose", "version", "outputs", "output", "o"}
+
+	tests := []struct {
+		name        string
+		input       string
+		maxDistance int
+		want        []string
+	}{
+		{"typo", "timout", 2, []string{"timeout"}},
+		{"prefix", "ver", 2, []string{"verbose", "version"}},
+		{"closest first", "outpu", 2, []string{"output", "outputs"}},
+		{"short names not suggested for unrelated input", "x", 2, []string{}},
+		{"no match", "zzzzzz", 2, []string{}},
+		{"disabled", "timout", 0, nil},
+		{"threshold", "tmout", 1, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.input, candidates, tt.maxDistance)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfiguration_OptionNames(t *testing.T) {
+	config := Configuration{
+		Groups: map[string]*Group{
+			"Default": {
+				Name: "Default",
+				Options: []*Option{
+					{Short: "t", Long: "timeout"},
+					{Long: "secret", Hidden: true},
+					{Long: "old", Deprecated: "renamed"},
+				},
+			},
+			"Hidden": {Name: "Hidden", Priority: 1, Hidden: true, Options: []*Option{{Long: "internal"}}},
+		},
+	}
+
+	want := []string{"t", "timeout"}
+	if got := config.OptionNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("OptionNames() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Style is a set of ANSI attributes applied to a single element of the usage output.
+// An empty Style renders the text without any color codes.
+type Style []color.Attribute
+
+// Theme defines the styles used by the ColorFormatter for each element of the
+// usage output. Themes allow applications to match their branding or to pick
+// colors that remain readable on light terminal backgrounds.
+type Theme struct {
+	Usage       Style // "Usage:" label at the start of the usage line
+	Text        Style // General text such as the usage line, metadata values and group descriptions
+	Header      Style // Section headers such as "Options:" and "Arguments:"
+	GroupHeader Style // Option group names
+	Option      Style // Option and argument names
+	Default     Style // Option default values
+	Description Style // Option and argument descriptions
+	Error       Style // Error messages
+	Warning     Style // Warning messages such as deprecation notices
+}
+
+// DefaultTheme returns the theme used by the ColorFormatter when no theme is
+// configured. It is designed for terminals with a dark background.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Usage:       Style{color.FgHiBlue, color.Bold},
+		Text:        Style{color.FgHiWhite},
+		Header:      Style{color.FgHiBlue, color.Bold},
+		GroupHeader: Style{color.FgHiBlue},
+		Option:      Style{color.FgGreen},
+		Default:     Style{color.FgHiCyan},
+		Description: Style{color.FgWhite},
+		Error:       Style{color.FgHiRed},
+		Warning:     Style{color.FgHiYellow},
+	}
+}
+
+// LightTheme returns a theme suited to terminals with a light background.
+// It avoids the bright white and cyan tones of the DefaultTheme which are
+// hard to read on white.
+func LightTheme() *Theme {
+	return &Theme{
+		Usage:       Style{color.FgBlue, color.Bold},
+		Text:        Style{color.FgBlack},
+		Header:      Style{color.FgBlue, color.Bold},
+		GroupHeader: Style{color.FgBlue},
+		Option:      Style{color.FgGreen},
+		Default:     Style{color.FgMagenta},
+		Description: Style{color.FgBlack},
+		Error:       Style{color.FgRed, color.Bold},
+		Warning:     Style{color.FgYellow, color.Bold},
+	}
+}
+
+// HighContrastTheme returns a theme that only uses bold primary colors, for
+// users who need maximum contrast regardless of the terminal background.
+func HighContrastTheme() *Theme {
+	return &Theme{
+		Usage:       Style{color.FgHiYellow, color.Bold},
+		Text:        Style{color.Bold},
+		Header:      Style{color.FgHiYellow, color.Bold},
+		GroupHeader: Style{color.FgHiYellow, color.Underline},
+		Option:      Style{color.FgHiGreen, color.Bold},
+		Default:     Style{color.FgHiCyan, color.Bold},
+		Description: Style{},
+		Error:       Style{color.FgHiRed, color.Bold},
+		Warning:     Style{color.FgHiYellow, color.Bold},
+	}
+}
+
+// MonochromeTheme returns a theme that uses only bold and underline attributes.
+// It keeps the structure of the output visible without relying on colors.
+func MonochromeTheme() *Theme {
+	return &Theme{
+		Usage:       Style{color.Bold},
+		Text:        Style{},
+		Header:      Style{color.Bold},
+		GroupHeader: Style{color.Underline},
+		Option:      Style{color.Bold},
+		Default:     Style{},
+		Description: Style{},
+		Error:       Style{color.Bold},
+		Warning:     Style{color.Underline},
+	}
+}
+
+// ColorEnabled reports whether colored output should be written to w.
+// The decision follows the common conventions, in order of precedence:
+//   - FORCE_COLOR set to anything other than "0" or "false" enables color
+//   - NO_COLOR set to any non-empty value disables color
+//   - CLICOLOR_FORCE set to anything other than "0" enables color
+//   - CLICOLOR=0 or TERM=dumb disables color
+//
+// Otherwise color is only enabled when w is a terminal.
+func ColorEnabled(w io.Writer) bool {
+	if v := os.Getenv("FORCE_COLOR"); v != "" {
+		v = strings.ToLower(strings.TrimSpace(v))
+		return v != "0" && v != "false"
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a file descriptor attached to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// painter returns a color.Color for the style with coloring explicitly enabled or
+// disabled, so the result does not depend on the package level color.NoColor
+// setting which only reflects the state of os.Stdout.
+func (s Style) painter(enabled bool) *color.Color {
+	c := color.New(s...)
+	if enabled && len(s) > 0 {
+		c.EnableColor()
+	} else {
+		c.DisableColor()
+	}
+	return c
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// clearColorEnv resets all environment variables consulted by ColorEnabled.
+func clearColorEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"FORCE_COLOR", "NO_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "TERM"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{
+			name: "non terminal writer",
+			env:  map[string]string{},
+			want: false,
+		},
+		{
+			name: "force color",
+			env:  map[string]string{"FORCE_COLOR": "1"},
+			want: true,
+		},
+		{
+			name: "force color disabled",
+			env:  map[string]string{"FORCE_COLOR": "0"},
+			want: false,
+		},
+		{
+			name: "force color wins over no color",
+			env:  map[string]string{"FORCE_COLOR": "true", "NO_COLOR": "1"},
+			want: true,
+		},
+		{
+			name: "no color wins over clicolor force",
+			env:  map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"},
+			want: false,
+		},
+		{
+			name: "clicolor force",
+			env:  map[string]string{"CLICOLOR_FORCE": "1"},
+			want: true,
+		},
+		{
+			name: "clicolor force zero",
+			env:  map[string]string{"CLICOLOR_FORCE": "0"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearColorEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var buf bytes.Buffer
+			if got := ColorEnabled(&buf); got != tt.want {
+				t.Errorf("ColorEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyle_Painter(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    Style
+		enabled  bool
+		wantANSI bool
+	}{
+		{
+			name:     "enabled style",
+			style:    Style{color.FgRed},
+			enabled:  true,
+			wantANSI: true,
+		},
+		{
+			name:     "disabled style",
+			style:    Style{color.FgRed},
+			enabled:  false,
+			wantANSI: false,
+		},
+		{
+			name:     "empty style",
+			style:    Style{},
+			enabled:  true,
+			wantANSI: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.style.painter(tt.enabled).Fprint(&buf, "text")
+			if got := strings.Contains(buf.String(), "\x1b["); got != tt.wantANSI {
+				t.Errorf("painter output %q contains ANSI codes = %v, want %v", buf.String(), got, tt.wantANSI)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package internal
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
 )
 
 // GetExecutableName returns the name of the current executable without extension.
@@ -61,3 +63,151 @@ func wrapText(text string, width int, prefixLen int) []string {
 	}
 	return lines
 }
+
+// optionNames returns the display form of an option's names, such as "-o, --output".
+// Options with only a long name are indented to line up with options that have
+// a short name when alignShort is true, and options with only a short name
+// are displayed without a trailing separator.
+func optionNames(option *Option, alignShort bool) string {
+	switch {
+	case option.Short != "" && option.Long != "":
+		return fmt.Sprintf("-%s, --%s", option.Short, option.Long)
+	case option.Short != "":
+		return "-" + option.Short
+	case alignShort:
+		return "    --" + option.Long
+	default:
+		return "--" + option.Long
+	}
+}
+
+// optionColumnWidths calculates the layout of the option name and default value
+// columns used by the text formatters. hasShort reports whether any option has
+// a short name, in which case long-only names are indented to line up.
+func optionColumnWidths(options []*Option) (hasShort bool, nameWidth, defaultWidth int) {
+	for _, option := range options {
+		if option.Short != "" {
+			hasShort = true
+		}
+	}
+	for _, option := range options {
+		if n := len(optionNames(option, hasShort)); n > nameWidth {
+			nameWidth = n
+		}
+		if n := len(optionDefault(option)); n > defaultWidth {
+			defaultWidth = n
+		}
+	}
+	return
+}
+
+// argumentNameWidth returns the width of the name column of the arguments
+// section, the length of the longest argument name.
+func argumentNameWidth(arguments []*Argument) (nameWidth int) {
+	for _, argument := range arguments {
+		if len(argument.Name) > nameWidth {
+			nameWidth = len(argument.Name)
+		}
+	}
+	return
+}
+
+// optionDefault returns the display form of an option's default value.
+// Empty defaults are shown as "-" so the columns stay aligned, and the
+// defaults of secret options are masked.
+func optionDefault(option *Option) string {
+	value := fmt.Sprintf("%v", option.Default)
+	if option.Default == nil || value == "" {
+		return "-"
+	}
+	return option.DisplayValue(value)
+}
+
+// codebaseDetails returns the branch and modified state that follow the commit
+// hash in the usage output, e.g. " (main, modified)", or an empty string when
+// neither is known.
+func codebaseDetails(c *Configuration) string {
+	var details []string
+	if c.ApplicationBranch != "" {
+		details = append(details, c.ApplicationBranch)
+	}
+	if c.ApplicationModified {
+		details = append(details, "modified")
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+// EnvVarName builds an environment variable name from the given parts by
+// upper-casing them, replacing every character that is not a letter or a
+// digit with an underscore and joining the parts with underscores.
+// For example EnvVarName("my-app", "help format") returns "MY_APP_HELP_FORMAT".
+func EnvVarName(parts ...string) string {
+	var names []string
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		name := strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '_'
+		}, part)
+		names = append(names, strings.ToUpper(name))
+	}
+	return strings.Join(names, "_")
+}
+
+// KebabCase converts a Go identifier such as "MaxRetries" or "HTTPProxy" to
+// the lower case, dash separated form used for option names, e.g.
+// "max-retries" and "http-proxy".
+func KebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteRune('-')
+			}
+			r = unicode.ToLower(r)
+		} else if r == '_' {
+			r = '-'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Dashed returns the option name with the dashes used on the command line,
+// a single dash for single character names and two dashes otherwise.
+func Dashed(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// Synopsis returns the usage line of the application without the "Usage: "
+// prefix, e.g. "myapp [OPTIONS] [ARGUMENTS]".
+func Synopsis(c *Configuration) string {
+	return c.ApplicationName + " [OPTIONS] [ARGUMENTS]"
+}
+
+// HelpHint returns the line pointing the user to the full usage information,
+// shown at the end of concise error output.
+func HelpHint(c *Configuration) string {
+	return fmt.Sprintf("Run '%s --help' for more information.", c.ApplicationName)
+}
+
+// capitalize returns s with its first letter in upper case.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
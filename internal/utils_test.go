@@ -74,3 +74,118 @@ func TestWrapText(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		option     *Option
+		alignShort bool
+		want       string
+	}{
+		{"short and long", &Option{Short: "o", Long: "output"}, true, "-o, --output"},
+		{"short only", &Option{Short: "o"}, true, "-o"},
+		{"long only aligned", &Option{Long: "output"}, true, "    --output"},
+		{"long only", &Option{Long: "output"}, false, "--output"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := optionNames(tt.option, tt.alignShort); got != tt.want {
+				t.Errorf("optionNames() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionDefault(t *testing.T) {
+	tests := []struct {
+		name   string
+		option *Option
+		want   string
+	}{
+		{"empty string", &Option{Default: ""}, "-"},
+		{"nil", &Option{}, "-"},
+		{"bool", &Option{Default: false}, "false"},
+		{"int", &Option{Default: 10}, "10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := optionDefault(tt.option); got != tt.want {
+				t.Errorf("optionDefault() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionDefaultSecret(t *testing.T) {
+	if got := optionDefault(&Option{Default: "hunter2", Secret: true}); got != SecretMask {
+		t.Errorf("optionDefault() = %q, want %q", got, SecretMask)
+	}
+	if got := optionDefault(&Option{Default: "", Secret: true}); got != "-" {
+		t.Errorf("optionDefault() = %q, want %q", got, "-")
+	}
+}
+
+func TestCodebaseDetails(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Configuration
+		want   string
+	}{
+		{"nothing known", &Configuration{}, ""},
+		{"branch", &Configuration{ApplicationBranch: "main"}, " (main)"},
+		{"modified", &Configuration{ApplicationModified: true}, " (modified)"},
+		{"branch and modified", &Configuration{ApplicationBranch: "main", ApplicationModified: true}, " (main, modified)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codebaseDetails(tt.config); got != tt.want {
+				t.Errorf("codebaseDetails() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Timeout", "timeout"},
+		{"MaxRetries", "max-retries"},
+		{"HTTPProxy", "http-proxy"},
+		{"URL", "url"},
+		{"UserID", "user-id"},
+		{"Retry2Times", "retry2-times"},
+		{"dry_run", "dry-run"},
+	}
+
+	for _, tt := range tests {
+		if got := KebabCase(tt.input); got != tt.want {
+			t.Errorf("KebabCase(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEnvVarName(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts []string
+		want  string
+	}{
+		{"single part", []string{"timeout"}, "TIMEOUT"},
+		{"dashes and spaces", []string{"my-app", "help format"}, "MY_APP_HELP_FORMAT"},
+		{"empty parts skipped", []string{"", "app", "", "version"}, "APP_VERSION"},
+		{"dots", []string{"app.v2"}, "APP_V2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EnvVarName(tt.parts...); got != tt.want {
+				t.Errorf("EnvVarName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package internal
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	flagValueType       = reflect.TypeOf((*flag.Value)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// NewValue returns a flag.Value that reads and writes the addressable value v.
+// Types implementing flag.Value or encoding.TextUnmarshaler (through a pointer)
+// are used as they are. Otherwise bools, integers, unsigned integers, floats,
+// strings and time.Duration are supported, as well as slices of those types,
+// which accept comma separated values and may be repeated, and maps with keys
+// and values of those types, which accept comma separated key=value pairs.
+// The first value set on a slice or map replaces its default contents.
+func NewValue(v reflect.Value) (flag.Value, error) {
+	if !v.CanAddr() {
+		return nil, fmt.Errorf("value of type %s is not addressable", v.Type())
+	}
+	if value, ok := v.Addr().Interface().(flag.Value); ok {
+		return value, nil
+	}
+	if isScalarType(v.Type()) {
+		return &scalarValue{v: v}, nil
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		if isScalarType(v.Type().Elem()) {
+			return &sliceValue{v: v}, nil
+		}
+	case reflect.Map:
+		if isScalarType(v.Type().Key()) && isScalarType(v.Type().Elem()) {
+			return &mapValue{v: v}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// IsValueType reports whether values of type t are set from a single option
+// rather than being walked as a group of options, which is the case for
+// structs that implement flag.Value or encoding.TextUnmarshaler.
+func IsValueType(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+	return ptr.Implements(flagValueType) || ptr.Implements(textUnmarshalerType)
+}
+
+// ValueTypeName returns the name of the type of values accepted by an option
+// bound to a value of type t, e.g. "int", "duration" or "[]string".
+func ValueTypeName(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+	if IsValueType(t) {
+		return t.String()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "[]" + ValueTypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + ValueTypeName(t.Key()) + "]" + ValueTypeName(t.Elem())
+	}
+	return t.String()
+}
+
+// isScalarType reports whether a single string can be converted to type t.
+func isScalarType(t reflect.Type) bool {
+	if t == durationType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// setScalar converts s to the type of the addressable value v and stores it.
+func setScalar(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errParse
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errParse
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return numError(err)
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		v.SetString(s)
+	}
+	return nil
+}
+
+// formatScalar returns the string form of a value supported by setScalar.
+func formatScalar(v reflect.Value) string {
+	if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// errParse and errRange mirror the errors reported by the standard flag
+// package so that conversion errors read the same for every option.
+var (
+	errParse = errors.New("parse error")
+	errRange = errors.New("value out of range")
+)
+
+// numError converts a strconv error into errParse or errRange.
+func numError(err error) error {
+	if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+		return errRange
+	}
+	return errParse
+}
+
+// scalarValue is the flag.Value for a single value supported by setScalar.
+type scalarValue struct {
+	v reflect.Value
+}
+
+func (s *scalarValue) Set(value string) error {
+	return setScalar(s.v, value)
+}
+
+// Get returns the current value, implementing flag.Getter.
+func (s *scalarValue) Get() interface{} {
+	if !s.v.IsValid() {
+		return nil
+	}
+	return s.v.Interface()
+}
+
+func (s *scalarValue) String() string {
+	if !s.v.IsValid() {
+		return ""
+	}
+	return formatScalar(s.v)
+}
+
+// IsBoolFlag allows boolean values to be given without an argument.
+func (s *scalarValue) IsBoolFlag() bool {
+	return s.v.IsValid() && s.v.Kind() == reflect.Bool
+}
+
+// sliceValue is the flag.Value for a slice of scalar values.
+type sliceValue struct {
+	v   reflect.Value
+	set bool
+}
+
+func (s *sliceValue) Set(value string) error {
+	if !s.set {
+		s.v.Set(reflect.MakeSlice(s.v.Type(), 0, 0))
+		s.set = true
+	}
+	for _, part := range strings.Split(value, ",") {
+		elem := reflect.New(s.v.Type().Elem()).Elem()
+		if err := setScalar(elem, strings.TrimSpace(part)); err != nil {
+			return err
+		}
+		s.v.Set(reflect.Append(s.v, elem))
+	}
+	return nil
+}
+
+// Get returns the current value, implementing flag.Getter.
+func (s *sliceValue) Get() interface{} {
+	if !s.v.IsValid() {
+		return nil
+	}
+	return s.v.Interface()
+}
+
+func (s *sliceValue) String() string {
+	if !s.v.IsValid() {
+		return ""
+	}
+	parts := make([]string, s.v.Len())
+	for i := range parts {
+		parts[i] = formatScalar(s.v.Index(i))
+	}
+	return strings.Join(parts, ",")
+}
+
+// mapValue is the flag.Value for a map of scalar keys and values.
+type mapValue struct {
+	v   reflect.Value
+	set bool
+}
+
+func (m *mapValue) Set(value string) error {
+	if !m.set || m.v.IsNil() {
+		m.v.Set(reflect.MakeMap(m.v.Type()))
+		m.set = true
+	}
+	for _, pair := range strings.Split(value, ",") {
+		k, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("%q is not a key=value pair", pair)
+		}
+		key := reflect.New(m.v.Type().Key()).Elem()
+		if err := setScalar(key, strings.TrimSpace(k)); err != nil {
+			return err
+		}
+		elem := reflect.New(m.v.Type().Elem()).Elem()
+		if err := setScalar(elem, strings.TrimSpace(val)); err != nil {
+			return err
+		}
+		m.v.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+// Get returns the current value, implementing flag.Getter.
+func (m *mapValue) Get() interface{} {
+	if !m.v.IsValid() {
+		return nil
+	}
+	return m.v.Interface()
+}
+
+func (m *mapValue) String() string {
+	if !m.v.IsValid() {
+		return ""
+	}
+	pairs := make([]string, 0, m.v.Len())
+	iter := m.v.MapRange()
+	for iter.Next() {
+		key := reflect.New(m.v.Type().Key()).Elem()
+		key.Set(iter.Key())
+		elem := reflect.New(m.v.Type().Elem()).Elem()
+		elem.Set(iter.Value())
+		pairs = append(pairs, formatScalar(key)+"="+formatScalar(elem))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"flag"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewValue(t *testing.T) {
+	var target struct {
+		Bool     bool
+		Int      int
+		Int8     int8
+		Uint     uint
+		Float    float64
+		String   string
+		Duration time.Duration
+		Strings  []string
+		Ints     []int
+		Labels   map[string]string
+		Weights  map[string]float64
+		IP       net.IP
+	}
+	target.Strings = []string{"default"}
+
+	tests := []struct {
+		name   string
+		field  string
+		inputs []string
+		want   interface{}
+		str    string
+	}{
+		{"bool", "Bool", []string{"true"}, true, "true"},
+		{"int", "Int", []string{"42"}, 42, "42"},
+		{"hex int", "Int", []string{"0x10"}, 16, "16"},
+		{"int8", "Int8", []string{"-8"}, int8(-8), "-8"},
+		{"uint", "Uint", []string{"7"}, uint(7), "7"},
+		{"float", "Float", []string{"1.5"}, 1.5, "1.5"},
+		{"string", "String", []string{"hello"}, "hello", "hello"},
+		{"duration", "Duration", []string{"1m30s"}, 90 * time.Second, "1m30s"},
+		{"slice replaces default", "Strings", []string{"a", "b,c"}, []string{"a", "b", "c"}, "a,b,c"},
+		{"int slice", "Ints", []string{"1,2", "3"}, []int{1, 2, 3}, "1,2,3"},
+		{"map", "Labels", []string{"env=prod,team=core", "tier=1"}, map[string]string{"env": "prod", "team": "core", "tier": "1"}, "env=prod,team=core,tier=1"},
+		{"float map", "Weights", []string{"a=0.5"}, map[string]float64{"a": 0.5}, "a=0.5"},
+		{"text unmarshaler", "IP", []string{"10.0.0.1"}, net.ParseIP("10.0.0.1"), "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := reflect.ValueOf(&target).Elem().FieldByName(tt.field)
+			value, err := NewValue(field)
+			if err != nil {
+				t.Fatalf("NewValue() error = %v", err)
+			}
+			for _, input := range tt.inputs {
+				if err := value.Set(input); err != nil {
+					t.Fatalf("Set(%q) error = %v", input, err)
+				}
+			}
+			if got := field.Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value = %#v, want %#v", got, tt.want)
+			}
+			if got := value.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+			if getter, ok := value.(flag.Getter); !ok {
+				t.Errorf("value does not implement flag.Getter")
+			} else if got := getter.Get(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewValueErrors(t *testing.T) {
+	var target struct {
+		Int     int
+		Int8    int8
+		Labels  map[string]int
+		Nested  [][]string
+		Channel chan int
+	}
+	v := reflect.ValueOf(&target).Elem()
+
+	tests := []struct {
+		name  string
+		field string
+		input string
+	}{
+		{"invalid int", "Int", "abc"},
+		{"out of range", "Int8", "300"},
+		{"missing map separator", "Labels", "key"},
+		{"invalid map value", "Labels", "key=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := NewValue(v.FieldByName(tt.field))
+			if err != nil {
+				t.Fatalf("NewValue() error = %v", err)
+			}
+			if err := value.Set(tt.input); err == nil {
+				t.Errorf("Set(%q) expected error", tt.input)
+			}
+		})
+	}
+
+	for _, field := range []string{"Nested", "Channel"} {
+		if _, err := NewValue(v.FieldByName(field)); err == nil {
+			t.Errorf("NewValue(%s) expected unsupported type error", field)
+		}
+	}
+}
+
+func TestValueIsBoolFlag(t *testing.T) {
+	var target struct {
+		Bool bool
+		Int  int
+	}
+	v := reflect.ValueOf(&target).Elem()
+
+	for field, want := range map[string]bool{"Bool": true, "Int": false} {
+		value, _ := NewValue(v.FieldByName(field))
+		boolFlag, ok := value.(interface{ IsBoolFlag() bool })
+		if got := ok && boolFlag.IsBoolFlag(); got != want {
+			t.Errorf("IsBoolFlag() for %s = %v, want %v", field, got, want)
+		}
+	}
+}
+
+func TestValueTypeName(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{true, "bool"},
+		{int64(1), "int"},
+		{uint8(1), "uint"},
+		{float32(1), "float"},
+		{"", "string"},
+		{time.Second, "duration"},
+		{[]string{}, "[]string"},
+		{map[string]int{}, "map[string]int"},
+		{net.IP{}, "net.IP"},
+	}
+
+	for _, tt := range tests {
+		if got := ValueTypeName(reflect.TypeOf(tt.value)); got != tt.want {
+			t.Errorf("ValueTypeName(%T) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+// VersionFormat selects how version information is rendered.
+type VersionFormat string
+
+const (
+	VersionFormatShort    VersionFormat = "short"    // A concise single line such as "myapp 1.2.3"
+	VersionFormatDetailed VersionFormat = "detailed" // A block including build, commit and runtime details
+	VersionFormatJSON     VersionFormat = "json"     // A JSON document for automation
+)
+
+const (
+	// DefaultVersionTemplate is the text/template used for the short version output.
+	DefaultVersionTemplate = "{{.Name}} {{.Version}}\n"
+
+	// DefaultDetailedVersionTemplate is the text/template used for the detailed version output.
+	DefaultDetailedVersionTemplate = `{{.Name}} {{.Version}}
+{{- if .BuildDate}}
+  Build date: {{.BuildDate}}{{end}}
+{{- if .CommitHash}}
+  Commit:     {{.CommitHash}}{{if .Branch}} ({{.Branch}}){{end}}{{if .Modified}} (modified){{end}}{{end}}
+  Go version: {{.GoVersion}}
+  Platform:   {{.OS}}/{{.Arch}}
+`
+)
+
+// VersionInfo holds the data available to version templates. It is also the
+// structure of the JSON version output.
+type VersionInfo struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	BuildDate  string `json:"build_date,omitempty"`
+	CommitHash string `json:"commit_hash,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+	GoVersion  string `json:"go_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+}
+
+// NewVersionInfo collects the version information from the configuration and
+// the Go runtime. An empty application version is reported as "unknown".
+func NewVersionInfo(c *Configuration) VersionInfo {
+	version := c.ApplicationVersion
+	if version == "" {
+		version = "unknown"
+	}
+	return VersionInfo{
+		Name:       c.ApplicationName,
+		Version:    version,
+		BuildDate:  c.ApplicationBuildDate,
+		CommitHash: c.ApplicationCommitHash,
+		Branch:     c.ApplicationBranch,
+		Modified:   c.ApplicationModified,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// Render returns the version information in the requested format. The short
+// and detailed formats are rendered with the given text/template, the
+// templates default to DefaultVersionTemplate and DefaultDetailedVersionTemplate
+// when empty.
+func (v VersionInfo) Render(format VersionFormat, shortTemplate, detailedTemplate string) (string, error) {
+	var text string
+	switch format {
+	case VersionFormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	case VersionFormatDetailed:
+		text = detailedTemplate
+		if text == "" {
+			text = DefaultDetailedVersionTemplate
+		}
+	default:
+		text = shortTemplate
+		if text == "" {
+			text = DefaultVersionTemplate
+		}
+	}
+
+	tmpl, err := template.New("version").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid version template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, v); err != nil {
+		return "", fmt.Errorf("invalid version template: %w", err)
+	}
+	out := buf.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// VersionFlag is a flag.Value for the built-in version option. It behaves like
+// a boolean flag, so "--version" requests the short format, and also accepts
+// "--version=detailed" (or "full") and "--version=json".
+type VersionFlag struct {
+	Requested bool          // Whether the version option was used
+	Format    VersionFormat // The requested format
+}
+
+// String returns the requested format, or an empty string if none was requested.
+func (v *VersionFlag) String() string {
+	if v == nil || !v.Requested {
+		return ""
+	}
+	return string(v.Format)
+}
+
+// Set records the version request. Boolean values request the short format.
+func (v *VersionFlag) Set(value string) error {
+	switch strings.ToLower(value) {
+	case "true", "1", "short":
+		v.Requested, v.Format = true, VersionFormatShort
+	case "false", "0":
+		v.Requested, v.Format = false, ""
+	case "detailed", "full", "long":
+		v.Requested, v.Format = true, VersionFormatDetailed
+	case "json":
+		v.Requested, v.Format = true, VersionFormatJSON
+	default:
+		return fmt.Errorf("must be one of short, detailed or json")
+	}
+	return nil
+}
+
+// IsBoolFlag allows the version option to be used without a value.
+func (v *VersionFlag) IsBoolFlag() bool {
+	return true
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewVersionInfo(t *testing.T) {
+	info := NewVersionInfo(&Configuration{ApplicationName: "myapp"})
+	if info.Version != "unknown" {
+		t.Errorf("Version = %q, want %q", info.Version, "unknown")
+	}
+	if info.GoVersion != runtime.Version() || info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+		t.Errorf("runtime information = %q %q %q, want the current runtime", info.GoVersion, info.OS, info.Arch)
+	}
+}
+
+func TestVersionInfo_Render(t *testing.T) {
+	info := VersionInfo{
+		Name:       "myapp",
+		Version:    "1.2.3",
+		BuildDate:  "2024-01-01",
+		CommitHash: "abc123",
+		Branch:     "main",
+		GoVersion:  "go1.21.0",
+		OS:         "linux",
+		Arch:       "amd64",
+	}
+
+	tests := []struct {
+		name     string
+		format   VersionFormat
+		short    string
+		detailed string
+		want     string
+	}{
+		{
+			name:   "short",
+			format: VersionFormatShort,
+			want:   "myapp 1.2.3\n",
+		},
+		{
+			name:   "detailed",
+			format: VersionFormatDetailed,
+			want:   "myapp 1.2.3\n  Build date: 2024-01-01\n  Commit:     abc123 (main)\n  Go version: go1.21.0\n  Platform:   linux/amd64\n",
+		},
+		{
+			name:   "custom short template",
+			format: VersionFormatShort,
+			short:  "{{.Name}} version {{.Version}} ({{.CommitHash}})",
+			want:   "myapp version 1.2.3 (abc123)\n",
+		},
+		{
+			name:     "custom detailed template",
+			format:   VersionFormatDetailed,
+			detailed: "{{.Version}} {{.OS}}\n",
+			want:     "1.2.3 linux\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := info.Render(tt.format, tt.short, tt.detailed)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		got, err := info.Render(VersionFormatJSON, "", "")
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		var decoded VersionInfo
+		if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+			t.Fatalf("Render() wrote invalid JSON: %v", err)
+		}
+		if decoded != info {
+			t.Errorf("Render() = %+v, want %+v", decoded, info)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := info.Render(VersionFormatShort, "{{.Missing", "")
+		if err == nil || !strings.Contains(err.Error(), "invalid version template") {
+			t.Errorf("Render() error = %v, want an invalid template error", err)
+		}
+	})
+}
+
+func TestVersionFlag_Set(t *testing.T) {
+	tests := []struct {
+		value         string
+		wantRequested bool
+		wantFormat    VersionFormat
+		wantErr       bool
+	}{
+		{"true", true, VersionFormatShort, false},
+		{"full", true, VersionFormatDetailed, false},
+		{"detailed", true, VersionFormatDetailed, false},
+		{"JSON", true, VersionFormatJSON, false},
+		{"false", false, "", false},
+		{"xml", false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			v := &VersionFlag{}
+			err := v.Set(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if v.Requested != tt.wantRequested || v.Format != tt.wantFormat {
+				t.Errorf("Set(%q) = %v %q, want %v %q", tt.value, v.Requested, v.Format, tt.wantRequested, tt.wantFormat)
+			}
+		})
+	}
+}
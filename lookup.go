@@ -0,0 +1,108 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// Option describes a registered option. Options are returned by LookupOption,
+// Options, Visit and VisitAll.
+type Option = internal.Option
+
+// Argument describes a registered positional argument.
+type Argument = internal.Argument
+
+// LookupOption returns the option registered with the given short or long
+// name, with or without leading dashes, or nil if there is none.
+func (s *Usage) LookupOption(name string) *Option {
+	option, _ := s.configuration.FindOption(strings.TrimLeft(name, "-"))
+	return option
+}
+
+// LookupArgument returns the positional argument with the given name, or nil
+// if there is none.
+func (s *Usage) LookupArgument(name string) *Argument {
+	for _, argument := range s.configuration.SortedArguments() {
+		if argument.Name == name {
+			return argument
+		}
+	}
+	return nil
+}
+
+// Options returns all registered options, including hidden ones, in the order
+// they are displayed in the usage output.
+func (s *Usage) Options() []*Option {
+	return s.configuration.Options()
+}
+
+// Arguments returns all registered positional arguments ordered by position.
+func (s *Usage) Arguments() []*Argument {
+	return s.configuration.SortedArguments()
+}
+
+// VisitAll calls fn for every registered option in display order with the
+// current value of the option in its command line form. Values of secret
+// options are passed unmasked, use option.DisplayValue before logging them.
+func (s *Usage) VisitAll(fn func(option *Option, value string)) {
+	for _, option := range s.configuration.Options() {
+		fn(option, s.optionValue(option))
+	}
+}
+
+// Visit calls fn, in display order, for every option that was set on the
+// command line, from a secret file, through its environment variable, from a
+// profile or configuration file, at a prompt or with Set, with the current
+// value of the option in its command line form. Options left at their default
+// are skipped.
+func (s *Usage) Visit(fn func(option *Option, value string)) {
+	set := s.setOptions()
+	for _, option := range s.configuration.Options() {
+		if set[option] {
+			fn(option, s.optionValue(option))
+		}
+	}
+}
+
+// IsSet reports whether the option with the given short or long name was set
+// on the command line, from a secret file, through its environment variable,
+// from a profile or configuration file, at a prompt or with Set, rather than
+// left at its default.
+func (s *Usage) IsSet(name string) bool {
+	option := s.LookupOption(name)
+	return option != nil && s.setOptions()[option]
+}
+
+// Set sets the value of the option with the given short or long name, with or
+// without leading dashes, as if it had been given on the command line. The
+// value is converted and validated in the same way, an unknown option results
+// in a *UsageError wrapping ErrOptionNotFound and an invalid value, or one
+// that is not among the choices of the option, in a *UsageError of kind
+// invalid_value.
+func (s *Usage) Set(name string, value string) error {
+	name = strings.TrimLeft(name, "-")
+	if s.LookupOption(name) == nil || s.flagSet.Lookup(name) == nil {
+		err := &internal.UsageError{
+			Kind:    internal.ErrorKindUnknownOption,
+			Option:  name,
+			Message: fmt.Sprintf("flag provided but not defined: %s", internal.Dashed(name)),
+			Err:     &internal.UnknownOptionError{Location: internal.NoLocation, Name: name, Err: ErrOptionNotFound},
+		}
+		err.SuggestOptions(s.configuration, s.suggestionDistance)
+		return err
+	}
+	if err := s.setValue(s.LookupOption(name), name, value); err != nil {
+		return err
+	}
+	return nil
+}
+
+// optionValue returns the current value of option in its command line form.
+func (s *Usage) optionValue(option *Option) string {
+	if f := s.flagSet.Lookup(option.Name()); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package usage_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// addLookupOptions adds the options and arguments the lookup tests use to sage.
+func addLookupOptions(t *testing.T, sage *usage.Usage) {
+	t.Helper()
+	group := sage.AddGroup(1, "Request", "Request options")
+	_, err := sage.AddIntegerOptionE("t", "timeout", 10, "Timeout in seconds", "", group)
+	assert.NoError(t, err)
+	_, err = sage.AddBooleanOptionE("v", "verbose", false, "Verbose output", "", nil)
+	assert.NoError(t, err)
+	_, err = sage.AddStringOptionE("o", "output", "", "Output file", "", nil)
+	assert.NoError(t, err)
+	sage.AddArgument(2, "dest", "Destination", "")
+	sage.AddArgument(1, "src", "Source", "")
+}
+
+func TestLookupOption(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addLookupOptions(t, sage)
+
+	for _, name := range []string{"t", "timeout", "-t", "--timeout"} {
+		option := sage.LookupOption(name)
+		if assert.NotNil(t, option, name) {
+			assert.Equal(t, "timeout", option.Long)
+		}
+	}
+	assert.Nil(t, sage.LookupOption("missing"))
+
+	argument := sage.LookupArgument("src")
+	if assert.NotNil(t, argument) {
+		assert.Equal(t, 1, argument.Position)
+	}
+	assert.Nil(t, sage.LookupArgument("missing"))
+}
+
+func TestOptionsAndArguments(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addLookupOptions(t, sage)
+
+	var names []string
+	for _, option := range sage.Options() {
+		names = append(names, option.Long)
+	}
+	assert.Equal(t, []string{"verbose", "output", "timeout"}, names)
+
+	var arguments []string
+	for _, argument := range sage.Arguments() {
+		arguments = append(arguments, argument.Name)
+	}
+	assert.Equal(t, []string{"src", "dest"}, arguments)
+}
+
+func TestVisit(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addLookupOptions(t, sage)
+	assert.NoError(t, sage.ParseArgs([]string{"-t", "5", "--output", "a.txt"}))
+
+	set := map[string]string{}
+	sage.Visit(func(option *usage.Option, value string) {
+		set[option.Long] = value
+	})
+	assert.Equal(t, map[string]string{"timeout": "5", "output": "a.txt"}, set)
+	assert.True(t, sage.IsSet("timeout"))
+	assert.False(t, sage.IsSet("verbose"))
+
+	all := map[string]string{}
+	sage.VisitAll(func(option *usage.Option, value string) {
+		all[option.Long] = value
+	})
+	assert.Equal(t, map[string]string{"timeout": "5", "output": "a.txt", "verbose": "false"}, all)
+}
+
+func TestSet(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addLookupOptions(t, sage)
+	timeout := sage.LookupOption("timeout")
+	assert.NotNil(t, timeout)
+
+	assert.NoError(t, sage.Set("--timeout", "30"))
+	assert.True(t, sage.IsSet("t"))
+
+	values := map[string]string{}
+	sage.Visit(func(option *usage.Option, value string) {
+		values[option.Long] = value
+	})
+	assert.Equal(t, map[string]string{"timeout": "30"}, values)
+
+	err := sage.Set("timeout", "abc")
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindInvalidValue, usageErr.Kind)
+		assert.Equal(t, "timeout", usageErr.Option)
+	}
+
+	err = sage.Set("timout", "5")
+	assert.ErrorIs(t, err, usage.ErrOptionNotFound)
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindUnknownOption, usageErr.Kind)
+		assert.Equal(t, []string{"--timeout"}, usageErr.Suggestions)
+	}
+}
@@ -4,6 +4,7 @@ package pkg
 import (
 	"github.com/bgrewell/usage/internal"
 	"io"
+	"strings"
 )
 
 // NewStandardFormatter creates a plain text formatter that outputs usage
@@ -41,3 +42,37 @@ func NewColorFormatter(output, error io.Writer, config *internal.Configuration)
 		Configuration: config,
 	}
 }
+
+// NewJSONFormatter creates a formatter that emits the usage structure and
+// errors as JSON documents, for consumption by IDE plugins and wrapper tools.
+//
+// Parameters:
+//   - output: the writer for normal usage output (typically os.Stdout)
+//   - error: the writer for error messages (typically os.Stderr)
+//   - config: the configuration containing application and option information
+//
+// Returns a Formatter that can be used with WithFormatter option.
+func NewJSONFormatter(output, error io.Writer, config *internal.Configuration) internal.Formatter {
+	return &internal.JSONFormatter{
+		Output:        output,
+		Error:         error,
+		Configuration: config,
+	}
+}
+
+// NewFormatterByName creates one of the built-in formatters by name. The
+// recognized names are "color", "plain" (or "standard", "text") and "json",
+// compared case-insensitively.
+//
+// Returns the formatter and true, or nil and false if the name is not recognized.
+func NewFormatterByName(name string, output, error io.Writer, config *internal.Configuration) (internal.Formatter, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "color", "colour":
+		return NewColorFormatter(output, error, config), true
+	case "plain", "standard", "text":
+		return NewStandardFormatter(output, error, config), true
+	case "json":
+		return NewJSONFormatter(output, error, config), true
+	}
+	return nil, false
+}
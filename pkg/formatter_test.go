@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/bgrewell/usage/internal"
 	"testing"
 )
@@ -27,3 +28,45 @@ func TestNewColorFormatter(t *testing.T) {
 		t.Errorf("NewColorFormatter() = %T; want *internal.ColorFormatter", formatter)
 	}
 }
+
+func TestNewJSONFormatter(t *testing.T) {
+	var output, error bytes.Buffer
+	config := &internal.Configuration{}
+
+	formatter := NewJSONFormatter(&output, &error, config)
+
+	if _, ok := formatter.(*internal.JSONFormatter); !ok {
+		t.Errorf("NewJSONFormatter() = %T; want *internal.JSONFormatter", formatter)
+	}
+}
+
+func TestNewFormatterByName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   interface{}
+		wantOK bool
+	}{
+		{"json", &internal.JSONFormatter{}, true},
+		{"JSON", &internal.JSONFormatter{}, true},
+		{"plain", &internal.StandardFormatter{}, true},
+		{"text", &internal.StandardFormatter{}, true},
+		{"color", &internal.ColorFormatter{}, true},
+		{"xml", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output, error bytes.Buffer
+			formatter, ok := NewFormatterByName(tt.name, &output, &error, &internal.Configuration{})
+			if ok != tt.wantOK {
+				t.Fatalf("NewFormatterByName(%q) ok = %v; want %v", tt.name, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if fmt.Sprintf("%T", formatter) != fmt.Sprintf("%T", tt.want) {
+				t.Errorf("NewFormatterByName(%q) = %T; want %T", tt.name, formatter, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package pkg
+
+import "github.com/bgrewell/usage/internal"
+
+// NewDefaultTheme returns the theme used by the color formatter when no theme
+// is configured. It is designed for terminals with a dark background.
+//
+// The returned Theme can be modified before being passed to WithTheme.
+func NewDefaultTheme() *internal.Theme {
+	return internal.DefaultTheme()
+}
+
+// NewLightTheme returns a theme designed for terminals with a light background.
+func NewLightTheme() *internal.Theme {
+	return internal.LightTheme()
+}
+
+// NewHighContrastTheme returns a theme using only bold, bright colors for
+// maximum readability.
+func NewHighContrastTheme() *internal.Theme {
+	return internal.HighContrastTheme()
+}
+
+// NewMonochromeTheme returns a theme using only bold and underline attributes.
+func NewMonochromeTheme() *internal.Theme {
+	return internal.MonochromeTheme()
+}
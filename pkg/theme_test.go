@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+func TestNewThemes(t *testing.T) {
+	themes := map[string]func() *internal.Theme{
+		"default":       NewDefaultTheme,
+		"light":         NewLightTheme,
+		"high contrast": NewHighContrastTheme,
+		"monochrome":    NewMonochromeTheme,
+	}
+
+	for name, factory := range themes {
+		t.Run(name, func(t *testing.T) {
+			theme := factory()
+			if theme == nil {
+				t.Fatalf("%s theme factory returned nil", name)
+			}
+			if len(theme.Header) == 0 {
+				t.Errorf("%s theme has no header style", name)
+			}
+		})
+	}
+}
+
+func TestNewThemeReturnsCopy(t *testing.T) {
+	theme := NewDefaultTheme()
+	theme.Header = nil
+
+	if len(NewDefaultTheme().Header) == 0 {
+		t.Error("modifying a returned theme changed the built-in theme")
+	}
+}
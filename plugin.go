@@ -0,0 +1,128 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// PLUGIN_SECTION is the title of the help section listing plugin commands.
+const PLUGIN_SECTION = "Plugin commands"
+
+// Plugin is an external command named "<app>-<command>", see WithPlugins.
+type Plugin = internal.Plugin
+
+// pluginCall is a plugin selected by the command line together with the
+// arguments that follow its name.
+type pluginCall struct {
+	plugin Plugin
+	args   []string
+}
+
+// WithPlugins enables git-style plugin commands. Executables named after the
+// application followed by a dash and the command, e.g. "myapp-deploy", are
+// looked up in dirs and then in the directories of PATH, and listed in the
+// "Plugin commands" section of the help. When the first positional argument
+// names a plugin, e.g. "myapp --verbose deploy --force", Run and Parse
+// execute it with the arguments that follow its name, the standard streams
+// and the environment, extended with the values of all options that are not
+// secret as <APP>_<OPTION> variables, e.g. MYAPP_VERBOSE=true. Run returns
+// the exit code of the plugin as an ExitError and Parse exits with it.
+//
+// Plugins take precedence over positional arguments, which are left empty,
+// and required options and arguments are not checked, as they apply to the
+// application itself rather than to its plugins.
+func WithPlugins(dirs ...string) UsageOption {
+	return func(u *Usage) {
+		u.pluginsEnabled = true
+		u.pluginDirs = dirs
+	}
+}
+
+// Plugins returns the plugin commands that were found, ordered by name. It
+// returns nil unless plugins are enabled with WithPlugins.
+func (s *Usage) Plugins() []Plugin {
+	s.loadPlugins()
+	return append([]Plugin(nil), s.plugins...)
+}
+
+// loadPlugins looks up the plugins once and adds the help section listing
+// them.
+func (s *Usage) loadPlugins() {
+	if !s.pluginsEnabled || s.plugins != nil {
+		return
+	}
+	dirs := append(append([]string(nil), s.pluginDirs...), filepath.SplitList(os.Getenv("PATH"))...)
+	s.plugins = internal.FindPlugins(s.configuration.ApplicationName+"-", dirs)
+	if len(s.plugins) == 0 {
+		s.plugins = []Plugin{}
+		return
+	}
+	names := make([]string, len(s.plugins))
+	for i, plugin := range s.plugins {
+		names[i] = plugin.Name
+	}
+	s.AddSection(PLUGIN_SECTION, strings.Join(names, "\n"), SECTION_BOTTOM)
+}
+
+// selectPlugin returns the call of the plugin named by the first of the
+// positional arguments, or nil if there is none.
+func (s *Usage) selectPlugin(positional []string) *pluginCall {
+	if len(positional) == 0 {
+		return nil
+	}
+	s.loadPlugins()
+	for _, plugin := range s.plugins {
+		if plugin.Name == positional[0] {
+			return &pluginCall{plugin: plugin, args: positional[1:]}
+		}
+	}
+	return nil
+}
+
+// runPlugin executes the selected plugin and waits for it to exit. Signals
+// from the terminal reach the plugin directly, so it is not stopped when ctx
+// is cancelled.
+func (s *Usage) runPlugin(ctx context.Context) error {
+	call := s.pluginCall
+	cmd := exec.Command(call.plugin.Path, call.args...)
+	cmd.Stdin = s.input
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), s.pluginEnv()...)
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			// The plugin was terminated by a signal
+			code = EXIT_INTERRUPTED
+		}
+		return &ExitError{Code: code}
+	}
+	if err != nil {
+		return fmt.Errorf("cannot run plugin command %s: %w", call.plugin.Name, err)
+	}
+	return nil
+}
+
+// pluginEnv returns the values of the options that are not secret as
+// environment variables named after the application and the option.
+func (s *Usage) pluginEnv() []string {
+	var env []string
+	for _, option := range s.configuration.Options() {
+		if option.Secret {
+			continue
+		}
+		name := internal.EnvVarName(s.configuration.ApplicationName, option.Name())
+		env = append(env, name+"="+s.optionValue(option))
+	}
+	return env
+}
@@ -0,0 +1,115 @@
+//go:build unix
+
+package usage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// writePlugin writes a plugin named greet running script to a new directory
+// and returns the directory and the file the plugin may write to, which is
+// passed in $PLUGIN_OUT.
+func writePlugin(t *testing.T, script string) (string, string) {
+	t.Helper()
+	dir, out := t.TempDir(), filepath.Join(t.TempDir(), "out")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "plugtest-greet"), []byte("#!/bin/sh\n"+script), 0o755))
+	t.Setenv("PATH", "")
+	t.Setenv("PLUGIN_OUT", out)
+	return dir, out
+}
+
+// addPluginOptions adds the options and the required target argument the
+// plugin tests use to sage.
+func addPluginOptions(t *testing.T, sage *usage.Usage) *string {
+	t.Helper()
+	_, err := sage.AddStringOptionE("n", "name", "", "Name", "", nil)
+	assert.NoError(t, err)
+	_, err = sage.String("token").Secret().Build()
+	assert.NoError(t, err)
+	target := sage.AddArgument(1, "target", "Target", "")
+	assert.NoError(t, sage.RequireArgument("target"))
+	return target
+}
+
+func TestPlugins(t *testing.T) {
+	dir, _ := writePlugin(t, "exit 0\n")
+	sage := usage.NewUsage(
+		usage.WithApplicationName("plugtest"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithPlugins(dir),
+	)
+	addPluginOptions(t, sage)
+	plugins := sage.Plugins()
+	assert.Len(t, plugins, 1)
+	assert.Equal(t, "greet", plugins[0].Name)
+
+	sections := sage.Model().SectionsAt(usage.SECTION_BOTTOM)
+	assert.Len(t, sections, 1)
+	assert.Equal(t, usage.PLUGIN_SECTION, sections[0].Title)
+	assert.Equal(t, "greet", sections[0].Body)
+}
+
+func TestRunPlugin(t *testing.T) {
+	script := `echo "$* $PLUGTEST_NAME token=$PLUGTEST_TOKEN" > "$PLUGIN_OUT"` + "\nexit 3\n"
+	dir, out := writePlugin(t, script)
+	sage := usage.NewUsage(
+		usage.WithApplicationName("plugtest"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithPlugins(dir),
+	)
+	target := addPluginOptions(t, sage)
+	handled := false
+	sage.Handle(func(context.Context) error {
+		handled = true
+		return nil
+	})
+
+	err := sage.RunArgs(context.Background(), []string{"--name", "bob", "--token", "s3cret", "greet", "--loud", "x"})
+	var exitErr *usage.ExitError
+	assert.True(t, errors.As(err, &exitErr))
+	assert.Nil(t, exitErr.Err)
+	assert.Equal(t, 3, usage.ExitCode(err))
+	assert.False(t, handled)
+	assert.Empty(t, *target)
+
+	data, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "--loud x bob token=\n", string(data))
+}
+
+func TestRunWithoutPlugin(t *testing.T) {
+	dir, out := writePlugin(t, "touch \"$PLUGIN_OUT\"\n")
+	sage := usage.NewUsage(
+		usage.WithApplicationName("plugtest"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithPlugins(dir),
+	)
+	target := addPluginOptions(t, sage)
+	handled := false
+	sage.Handle(func(context.Context) error {
+		handled = true
+		return nil
+	})
+
+	assert.NoError(t, sage.RunArgs(context.Background(), []string{"other"}))
+	assert.True(t, handled)
+	assert.Equal(t, "other", *target)
+	_, err := os.Stat(out)
+	assert.True(t, os.IsNotExist(err))
+}
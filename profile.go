@@ -0,0 +1,252 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+const (
+	// PROFILE_FLAG is the name of the option that selects a profile.
+	PROFILE_FLAG = "profile"
+
+	// ENV_PROFILE is the suffix of the environment variable that selects a
+	// profile. The full name is the upper-cased application name followed by
+	// this suffix, e.g. MYAPP_PROFILE=prod.
+	ENV_PROFILE = "PROFILE"
+
+	// PROFILE_CONFIG_SECTION is the section of configuration files that
+	// defines a profile, e.g. [profile "prod"].
+	PROFILE_CONFIG_SECTION = "profile"
+
+	// PROFILE_EXTENDS is the setting of a profile that names the profiles it
+	// is based on, separated by commas.
+	PROFILE_EXTENDS = "extends"
+
+	// PROFILE_SECTION is the title of the help section listing the profiles.
+	PROFILE_SECTION = "Profiles"
+)
+
+// WithProfiles enables profiles: named sets of option values defined in the
+// configuration files, see WithConfigFiles, that are selected with the
+// --profile option, the <APP>_PROFILE environment variable or a profile
+// setting before the first section of a configuration file:
+//
+//	[profile "base"]
+//	region = us-east-1
+//
+//	[profile "prod"]
+//	extends = base
+//	replicas = 3
+//
+// The values of a profile override those of the configuration files and are
+// overridden by environment variables and the command line. A profile based
+// on others with the extends setting overrides their values. The profiles are
+// listed in the "Profiles" section of the help.
+func WithProfiles() UsageOption {
+	return func(u *Usage) {
+		u.profilesEnabled = true
+	}
+}
+
+// Profiles returns the names of the profiles defined in the configuration
+// files, ordered by name.
+func (s *Usage) Profiles() []string {
+	_ = s.loadConfig()
+	return s.profileNames()
+}
+
+// profileNames returns the names of the profiles of the configuration files
+// read so far, ordered by name.
+func (s *Usage) profileNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, layer := range s.configLayers {
+		for _, entry := range layer {
+			if entry.Section == PROFILE_CONFIG_SECTION && !seen[entry.Subsection] {
+				seen[entry.Subsection] = true
+				names = append(names, entry.Subsection)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the name of the profile selected by the last parse, or an
+// empty string if no profile was selected.
+func (s *Usage) Profile() string {
+	return s.profile
+}
+
+// registerProfileFlag registers the option that selects a profile, unless
+// profiles are disabled or the name is already taken.
+func (s *Usage) registerProfileFlag() {
+	if !s.profilesEnabled || s.profileOption != nil || s.flagSet.Lookup(PROFILE_FLAG) != nil {
+		return
+	}
+	description := "Use the named profile of the configuration files"
+	s.flagSet.StringVar(&s.profile, PROFILE_FLAG, "", description)
+	s.profileOption = &internal.Option{
+		Long:        PROFILE_FLAG,
+		Default:     "",
+		Description: description,
+		Env:         internal.EnvVarName(s.configuration.ApplicationName, ENV_PROFILE),
+	}
+	_ = s.addOptionToGroup(s.profileOption, nil)
+}
+
+// updateProfileSection adds or updates the help section listing the
+// profiles, with the profiles they extend.
+func (s *Usage) updateProfileSection() {
+	names := s.profileNames()
+	if !s.profilesEnabled || len(names) == 0 {
+		return
+	}
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = name
+		if extends := s.profileExtends(name); len(extends) > 0 {
+			lines[i] += fmt.Sprintf(" (extends %s)", strings.Join(extends, ", "))
+		}
+	}
+	if s.profileSection == nil {
+		s.profileSection = &internal.Section{Title: PROFILE_SECTION, Position: SECTION_BOTTOM}
+		s.configuration.Sections = append(s.configuration.Sections, s.profileSection)
+	}
+	s.profileSection.Body = strings.Join(lines, "\n")
+}
+
+// applyProfile sets the options that have not been set from the selected
+// profile. The profile given on the command line or through the environment
+// takes precedence over a profile selected in the configuration files.
+func (s *Usage) applyProfile(args []string) error {
+	if !s.profilesEnabled {
+		return nil
+	}
+	if s.profileOption == nil {
+		s.profile = os.Getenv(internal.EnvVarName(s.configuration.ApplicationName, ENV_PROFILE))
+	} else if !s.setOptions()[s.profileOption] {
+		for _, layer := range s.configLayers {
+			for _, entry := range sectionEntries(layer, "", "") {
+				if option, _ := s.configuration.FindOption(entry.Key); option == s.profileOption {
+					s.profile = unquote(entry.Value)
+				}
+			}
+		}
+	}
+	if s.profile == "" {
+		return nil
+	}
+
+	if !s.hasProfile(s.profile) {
+		return s.profileError(args)
+	}
+	layers, err := s.profileLayers(s.profile, nil, "")
+	if err != nil {
+		return err
+	}
+	return s.applyLayers(layers)
+}
+
+// hasProfile reports whether a profile with the given name is defined.
+func (s *Usage) hasProfile(name string) bool {
+	for _, profile := range s.profileNames() {
+		if profile == name {
+			return true
+		}
+	}
+	return false
+}
+
+// profileError returns the error for selecting a profile that is not defined.
+func (s *Usage) profileError(args []string) *internal.UsageError {
+	constraint := "no profiles are defined"
+	if names := s.profileNames(); len(names) > 0 {
+		constraint = "must be one of " + strings.Join(names, ", ")
+	}
+	location := internal.NoLocation
+	if s.profileOption != nil {
+		location = internal.FindToken(s.configuration, args, s.profileOption)
+	}
+	usageErr := &internal.UsageError{
+		Kind:    internal.ErrorKindInvalidValue,
+		Option:  PROFILE_FLAG,
+		Message: fmt.Sprintf("invalid value %q for flag %s: %s", s.profile, internal.Dashed(PROFILE_FLAG), constraint),
+		Err: &internal.ConstraintError{
+			Location:   location,
+			Option:     s.profileOption,
+			Value:      s.profile,
+			Constraint: constraint,
+		},
+	}
+	if s.profileOption == nil {
+		usageErr.Source = "environment variable " + internal.EnvVarName(s.configuration.ApplicationName, ENV_PROFILE)
+	}
+	return usageErr
+}
+
+// profileExtends returns the names of the profiles the named profile extends,
+// as given by its last extends setting.
+func (s *Usage) profileExtends(name string) []string {
+	var extends []string
+	for _, layer := range s.configLayers {
+		for _, entry := range sectionEntries(layer, PROFILE_CONFIG_SECTION, name) {
+			if entry.Key != PROFILE_EXTENDS {
+				continue
+			}
+			extends = nil
+			for _, parent := range strings.Split(unquote(entry.Value), ",") {
+				if parent = strings.TrimSpace(parent); parent != "" {
+					extends = append(extends, parent)
+				}
+			}
+		}
+	}
+	return extends
+}
+
+// profileLayers returns the settings of the named profile as layers for
+// applyLayers: the layers of the profiles it extends, in order, followed by
+// its own settings in each configuration file. chain holds the profiles
+// being resolved to detect loops and source is where name was referenced.
+func (s *Usage) profileLayers(name string, chain []string, source string) ([][]internal.IniEntry, error) {
+	for _, profile := range chain {
+		if profile == name {
+			err := configError(fmt.Sprintf("profile loop: %s -> %s", strings.Join(chain, " -> "), name), nil)
+			err.Source = source
+			return nil, err
+		}
+	}
+	if !s.hasProfile(name) {
+		err := configError(fmt.Sprintf("profile %s extends unknown profile %s", chain[len(chain)-1], name), nil)
+		err.Source = source
+		return nil, err
+	}
+	chain = append(chain, name)
+
+	var layers [][]internal.IniEntry
+	var own [][]internal.IniEntry
+	for _, layer := range s.configLayers {
+		var entries []internal.IniEntry
+		for _, entry := range sectionEntries(layer, PROFILE_CONFIG_SECTION, name) {
+			if entry.Key == PROFILE_EXTENDS {
+				source = entry.Source
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		own = append(own, entries)
+	}
+	for _, parent := range s.profileExtends(name) {
+		parentLayers, err := s.profileLayers(parent, chain, source)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, parentLayers...)
+	}
+	return append(layers, own...), nil
+}
@@ -0,0 +1,135 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const profileConfig = `region = us-west-2
+replicas = 1
+
+[profile "base"]
+region = us-east-1
+debug = true
+
+[profile "prod"]
+extends = base
+replicas = 3
+debug = false
+`
+
+// addProfileOptions adds the options the profiles of profileConfig set to sage.
+func addProfileOptions(sage *usage.Usage) (*string, *int, *bool) {
+	region, _ := sage.AddStringOptionE("", "region", "", "Region", "", nil)
+	replicas, _ := sage.AddIntegerOptionE("", "replicas", 0, "Replicas", "", nil)
+	debug, _ := sage.AddBooleanOptionE("", "debug", false, "Debug", "", nil)
+	return region, replicas, debug
+}
+
+func TestProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		profile  string
+		region   string
+		replicas int
+		debug    bool
+	}{
+		{"none", nil, "", "", "us-west-2", 1, false},
+		{"flag", []string{"--profile", "base"}, "", "base", "us-east-1", 1, true},
+		{"extends", []string{"--profile", "prod"}, "", "prod", "us-east-1", 3, false},
+		{"environment", nil, "base", "base", "us-east-1", 1, true},
+		{"flag over environment", []string{"--profile", "prod"}, "base", "prod", "us-east-1", 3, false},
+		{"explicit flag", []string{"--profile", "prod", "--replicas", "5"}, "", "prod", "us-east-1", 5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_PROFILE", tt.env)
+			sage := usage.NewUsage(
+				usage.WithApplicationName("app"),
+				usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+				usage.WithoutVersionFlag(),
+				usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+				usage.WithConfigFiles(writeConfigFile(t, profileConfig)),
+				usage.WithProfiles(),
+			)
+			region, replicas, debug := addProfileOptions(sage)
+			assert.NoError(t, sage.ParseArgs(tt.args))
+			assert.Equal(t, tt.profile, sage.Profile())
+			assert.Equal(t, tt.region, *region)
+			assert.Equal(t, tt.replicas, *replicas)
+			assert.Equal(t, tt.debug, *debug)
+		})
+	}
+}
+
+func TestProfileFromConfigFile(t *testing.T) {
+	for _, selector := range []string{"profile = prod\n", "profile = \"prod\"\n"} {
+		sage := usage.NewUsage(
+			usage.WithApplicationName("app"),
+			usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+			usage.WithoutVersionFlag(),
+			usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+			usage.WithConfigFiles(writeConfigFile(t, selector+profileConfig)),
+			usage.WithProfiles(),
+		)
+		region, replicas, _ := addProfileOptions(sage)
+		assert.NoError(t, sage.ParseArgs(nil), selector)
+		assert.Equal(t, "prod", sage.Profile(), selector)
+		assert.Equal(t, "us-east-1", *region, selector)
+		assert.Equal(t, 3, *replicas, selector)
+	}
+}
+
+func TestProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		args    []string
+		want    string
+	}{
+		{"unknown profile", profileConfig, []string{"--profile", "dev"}, `invalid value "dev" for flag --profile: must be one of base, prod`},
+		{"no profiles", "", []string{"--profile", "dev"}, `invalid value "dev" for flag --profile: no profiles are defined`},
+		{"unknown parent", "[profile a]\nextends = b\n", []string{"--profile", "a"}, ":2: profile a extends unknown profile b"},
+		{"loop", "[profile a]\nextends = b\n[profile b]\nextends = a\n", []string{"--profile", "a"}, ":4: profile loop: a -> b -> a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sage := usage.NewUsage(
+				usage.WithApplicationName("app"),
+				usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+				usage.WithoutVersionFlag(),
+				usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+				usage.WithConfigFiles(writeConfigFile(t, tt.content)),
+				usage.WithProfiles(),
+			)
+			addProfileOptions(sage)
+			err := sage.ParseArgs(tt.args)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestProfileSection(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithConfigFiles(writeConfigFile(t, profileConfig)),
+		usage.WithProfiles(),
+	)
+	addProfileOptions(sage)
+	assert.Equal(t, []string{"base", "prod"}, sage.Profiles())
+	sections := sage.Model().SectionsAt(usage.SECTION_BOTTOM)
+	assert.Len(t, sections, 1)
+	assert.Equal(t, usage.PROFILE_SECTION, sections[0].Title)
+	assert.Equal(t, "base\nprod (extends base)", sections[0].Body)
+}
@@ -0,0 +1,88 @@
+package usage
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// WithPrompts asks the user for the values of required options and arguments
+// that were not provided, instead of failing, when stdin is a terminal. The
+// prompt shows the description and default value, options with choices are
+// presented as a menu, booleans as a yes/no confirmation and secret options
+// are read without echo. Prompting is disabled automatically when stdin is not
+// a terminal, e.g. in scripts, pipes and CI jobs.
+func WithPrompts() UsageOption {
+	return func(u *Usage) {
+		u.prompts = true
+	}
+}
+
+// WithPromptIO enables prompting for missing required values like WithPrompts,
+// reading the answers from in and writing the questions to out regardless of
+// whether they are terminals. It is mainly intended for tests.
+func WithPromptIO(in io.Reader, out io.Writer) UsageOption {
+	return func(u *Usage) {
+		u.prompts = true
+		u.prompter = &internal.Prompter{Input: in, Output: out}
+	}
+}
+
+// promptMissing asks for the values of the required options and arguments
+// that have not been provided, if prompting is enabled and possible.
+func (s *Usage) promptMissing() error {
+	if !s.prompts {
+		return nil
+	}
+	if s.prompter == nil {
+		if s.prompter = internal.NewTerminalPrompter(); s.prompter == nil {
+			s.prompts = false
+			return nil
+		}
+	}
+
+	set := s.setOptions()
+	for _, option := range s.configuration.Options() {
+		if !option.Required || set[option] {
+			continue
+		}
+		value, err := s.prompter.PromptOption(option)
+		if err != nil {
+			return promptError(option, nil, err)
+		}
+		if err := s.setValue(option, option.Name(), value); err != nil {
+			return err
+		}
+	}
+	for i, argument := range s.argumentDefs {
+		if !argument.Required || *s.arguments[i] != "" {
+			continue
+		}
+		value, err := s.prompter.PromptArgument(argument)
+		if err != nil {
+			return promptError(nil, argument, err)
+		}
+		*s.arguments[i] = value
+	}
+	return nil
+}
+
+// promptError converts an error reading the answer for a missing option or
+// argument into a UsageError.
+func promptError(option *internal.Option, argument *internal.Argument, err error) *internal.UsageError {
+	if argument != nil {
+		return &internal.UsageError{
+			Kind:     internal.ErrorKindMissingArgument,
+			Argument: argument.Name,
+			Message:  fmt.Sprintf("missing required argument %s: %v", argument.Name, err),
+			Err:      &internal.MissingArgumentError{Location: internal.NoLocation, Argument: argument, Err: err},
+		}
+	}
+	return &internal.UsageError{
+		Kind:    internal.ErrorKindMissingOption,
+		Option:  option.Name(),
+		Message: fmt.Sprintf("missing required option %s: %v", internal.Dashed(option.Name()), err),
+		Err:     &internal.MissingOptionError{Location: internal.NoLocation, Option: option, Err: err},
+	}
+}
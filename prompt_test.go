@@ -0,0 +1,110 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/internal"
+	"github.com/mattn/go-isatty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptMissingValues(t *testing.T) {
+	var out bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithPromptIO(strings.NewReader("s3cret\n2\ny\nhttps://example.com\n"), &out),
+	)
+	var token, format string
+	var force bool
+	sage.String("token").Help("API token").Required().Secret().Var(&token)
+	sage.String("format").Help("Output format").Choices("json", "text").Required().Var(&format)
+	sage.Bool("force").Help("Overwrite files").Required().Var(&force)
+	sage.String("name").Help("Name").Default("bowser").Var(new(string))
+	url := sage.AddArgument(1, "url", "The url to fetch", "")
+	assert.NoError(t, sage.RequireArgument("url"))
+
+	assert.NoError(t, sage.ParseArgs(nil))
+	assert.Equal(t, "s3cret", token)
+	assert.Equal(t, "text", format)
+	assert.True(t, force)
+	assert.Equal(t, "https://example.com", *url)
+	assert.Equal(t, "API token: Output format:\n  1) json\n  2) text\nChoose 1-2: Overwrite files? [y/N]: The url to fetch: ", out.String())
+}
+
+func TestPromptOnlyMissingValues(t *testing.T) {
+	var out bytes.Buffer
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithPromptIO(strings.NewReader(""), &out),
+	)
+	var token string
+	sage.String("token").Required().Var(&token)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--token", "abc"}))
+	assert.Equal(t, "abc", token)
+	assert.Empty(t, out.String())
+}
+
+func TestPromptEndOfInput(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithPromptIO(strings.NewReader(""), &bytes.Buffer{}),
+	)
+	sage.String("token").Required().Var(new(string))
+
+	err := sage.ParseArgs(nil)
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindMissingOption, usageErr.Kind)
+		assert.Equal(t, "token", usageErr.Option)
+	}
+}
+
+func TestPromptsDisabledWithoutTerminal(t *testing.T) {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		t.Skip("stdin is a terminal")
+	}
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithPrompts(),
+	)
+	sage.AddArgument(1, "url", "The url to fetch", "")
+	assert.NoError(t, sage.RequireArgument("url"))
+
+	err := sage.ParseArgs(nil)
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindMissingArgument, usageErr.Kind)
+		assert.Equal(t, "url", usageErr.Argument)
+	}
+	assert.ErrorIs(t, sage.RequireArgument("missing"), usage.ErrArgumentNotFound)
+}
+
+func TestChoices(t *testing.T) {
+	sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+	format, err := sage.String("format").Default("json").Choices("json", "text").Build()
+	assert.NoError(t, err)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--format", "text"}))
+	assert.Equal(t, "text", *format)
+
+	err = sage.ParseArgs([]string{"--format", "yaml"})
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindInvalidValue, usageErr.Kind)
+		assert.Equal(t, `invalid value "yaml" for flag --format: must be one of json, text`, usageErr.Error())
+	}
+
+	err = sage.ParseArgs([]string{"--format", "jsn"})
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, []string{"json"}, usageErr.Suggestions)
+		assert.Equal(t, `invalid value "jsn" for flag --format: must be one of json, text (did you mean json?)`, usageErr.Error())
+	}
+
+	assert.Error(t, sage.Set("format", "xml"))
+}
@@ -0,0 +1,247 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ExitCoder is implemented by errors that carry the exit code of the program,
+// such as ExitError and *exec.ExitError.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitError is an error with the exit code Execute exits with. Handlers return
+// it to choose the exit code of a failure:
+//
+//	return &usage.ExitError{Code: 3, Err: err}
+//
+// Execute prints Err through the formatter, an ExitError without Err makes it
+// exit silently, e.g. after the handler has reported the problem itself.
+type ExitError struct {
+	Code int   // Exit code of the program
+	Err  error // Underlying error, may be nil
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// interruptError is returned by RunArgs when the handler failed after a
+// signal cancelled its context. It matches ErrInterrupted and wraps the error
+// of the handler, usually context.Canceled.
+type interruptError struct {
+	signal os.Signal
+	err    error
+}
+
+func (e *interruptError) Error() string {
+	return fmt.Sprintf("%v by %s: %v", ErrInterrupted, e.signal, e.err)
+}
+
+func (e *interruptError) Is(target error) bool {
+	return target == ErrInterrupted
+}
+
+func (e *interruptError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code for an error returned by Run: EXIT_OK for
+// nil, ErrHelp, ErrVersion and ErrGenerateConfig, the code of an ExitCoder,
+// EXIT_INTERRUPTED for ErrInterrupted, EXIT_USAGE for a *UsageError or an
+// ErrorList of them and EXIT_FAILURE for any other error.
+func ExitCode(err error) int {
+	var coder ExitCoder
+	var usageErr *UsageError
+	switch {
+	case err == nil, errors.Is(err, ErrHelp), errors.Is(err, ErrVersion), errors.Is(err, ErrGenerateConfig):
+		return EXIT_OK
+	case errors.As(err, &coder):
+		return coder.ExitCode()
+	case errors.Is(err, ErrInterrupted):
+		return EXIT_INTERRUPTED
+	case errors.As(err, &usageErr):
+		return EXIT_USAGE
+	}
+	return EXIT_FAILURE
+}
+
+// Handle registers the function that Run calls after parsing. The handler
+// receives a context that carries the Usage, see FromContext and
+// ValueFromContext, and that is cancelled when the program is interrupted.
+func (s *Usage) Handle(handler func(ctx context.Context) error) {
+	s.handler = handler
+}
+
+// Execute runs the program like Run with a background context and exits if
+// it fails. Help, version and sample configuration requests are printed and
+// exit successfully, errors are printed through the formatter and exit with
+// the code returned by ExitCode. Execute returns normally when the handler
+// succeeds.
+func (s *Usage) Execute() {
+	if err := s.Run(context.Background()); err != nil {
+		s.exit(err)
+	}
+}
+
+// exit prints err like Execute and exits with the code returned by ExitCode.
+func (s *Usage) exit(err error) {
+	var exitErr *ExitError
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrHelp):
+		s.PrintUsage()
+	case errors.Is(err, ErrVersion):
+		s.PrintVersion()
+	case errors.Is(err, ErrGenerateConfig):
+		s.PrintSampleConfig()
+	case errors.As(err, &exitErr) && exitErr.Err == nil:
+	default:
+		s.formatter.PrintError(err)
+	}
+	os.Exit(ExitCode(err))
+}
+
+// Run parses the command-line arguments from os.Args and calls the handler
+// registered with Handle, or the plugin command it names, see RunArgs.
+func (s *Usage) Run(ctx context.Context) error {
+	return s.RunArgs(ctx, os.Args[1:])
+}
+
+// RunArgs parses args like ParseArgs and calls the handler registered with
+// Handle between the before-run and after-run hooks, see Invoke. Parse errors
+// are returned without calling the handler and ErrNoHandler is returned when
+// no handler has been registered. If the command line names a plugin
+// command, see WithPlugins, the plugin is run instead of the handler.
+//
+// The context passed to the handler carries the Usage and is cancelled when
+// the program receives SIGINT or SIGTERM, so the handler can shut down
+// gracefully. A second signal exits the program immediately with
+// EXIT_INTERRUPTED. If the handler fails after a signal, RunArgs returns an
+// error that matches ErrInterrupted and wraps the error of the handler.
+func (s *Usage) RunArgs(ctx context.Context, args []string) error {
+	if err := s.ParseArgs(args); err != nil {
+		return err
+	}
+	if s.pluginCall != nil {
+		return s.invoke(ctx, s.runPlugin)
+	}
+	if s.handler == nil {
+		return ErrNoHandler
+	}
+	return s.invoke(ctx, s.handler)
+}
+
+// invoke calls run with a context that carries the Usage and is cancelled by
+// SIGINT and SIGTERM, between the before-run and after-run hooks.
+func (s *Usage) invoke(ctx context.Context, run func(ctx context.Context) error) error {
+	ctx, stop := signalContext(NewContext(ctx, s))
+	err := s.Invoke(func() error {
+		return run(ctx)
+	})
+	if sig := stop(); sig != nil && err != nil {
+		return &interruptError{signal: sig, err: err}
+	}
+	return err
+}
+
+// signalContext returns a context that is cancelled on the first SIGINT or
+// SIGTERM, after which a second signal exits the program with
+// EXIT_INTERRUPTED. The returned function stops listening for signals,
+// cancels the context and returns the signal that cancelled it, if any.
+func signalContext(parent context.Context) (context.Context, func() os.Signal) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	received := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			received <- sig
+			cancel()
+		case <-done:
+			return
+		}
+		select {
+		case <-signals:
+			os.Exit(EXIT_INTERRUPTED)
+		case <-done:
+		}
+	}()
+
+	return ctx, func() os.Signal {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+		select {
+		case sig := <-received:
+			return sig
+		default:
+			return nil
+		}
+	}
+}
+
+// contextKey is the key of the Usage in a context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries u. RunArgs passes such a
+// context to the handler.
+func NewContext(ctx context.Context, u *Usage) context.Context {
+	return context.WithValue(ctx, contextKey{}, u)
+}
+
+// FromContext returns the Usage carried by ctx, if any.
+func FromContext(ctx context.Context) (*Usage, bool) {
+	u, ok := ctx.Value(contextKey{}).(*Usage)
+	return u, ok
+}
+
+// ValueFromContext returns the value of the option with the given short or
+// long name of the Usage carried by ctx, so code called by the handler does
+// not need the Usage or the option variables:
+//
+//	verbose, _ := usage.ValueFromContext[bool](ctx, "verbose")
+//
+// It returns false if ctx carries no Usage, there is no such option or its
+// value is not of type T.
+func ValueFromContext[T any](ctx context.Context, name string) (T, bool) {
+	var zero T
+	u, ok := FromContext(ctx)
+	if !ok {
+		return zero, false
+	}
+	option := u.LookupOption(name)
+	if option == nil {
+		return zero, false
+	}
+	f := u.flagSet.Lookup(option.Name())
+	if f == nil {
+		return zero, false
+	}
+	if getter, ok := f.Value.(interface{ Get() interface{} }); ok {
+		value, ok := getter.Get().(T)
+		return value, ok
+	}
+	value, ok := f.Value.(T)
+	return value, ok
+}
@@ -0,0 +1,127 @@
+package usage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunArgs(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	_, err := sage.AddIntegerOptionE("c", "count", 1, "Count", "", nil)
+	assert.NoError(t, err)
+	_, err = sage.Strings("tag").Build()
+	assert.NoError(t, err)
+	_, err = sage.String("token").Secret().Build()
+	assert.NoError(t, err)
+
+	var calls []string
+	sage.OnBeforeRun(func(*usage.Usage) error {
+		calls = append(calls, "before run")
+		return nil
+	})
+	sage.Handle(func(ctx context.Context) error {
+		u, ok := usage.FromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, sage, u)
+
+		count, ok := usage.ValueFromContext[int](ctx, "-c")
+		assert.True(t, ok)
+		tags, ok := usage.ValueFromContext[[]string](ctx, "tag")
+		assert.True(t, ok)
+		token, ok := usage.ValueFromContext[string](ctx, "token")
+		assert.True(t, ok)
+		calls = append(calls, fmt.Sprintf("handler %d %v %s", count, tags, token))
+
+		_, ok = usage.ValueFromContext[string](ctx, "count")
+		assert.False(t, ok)
+		_, ok = usage.ValueFromContext[int](ctx, "missing")
+		assert.False(t, ok)
+		return nil
+	})
+
+	assert.NoError(t, sage.RunArgs(context.Background(), []string{"-c", "3", "--tag", "a,b", "--token", "s3cret"}))
+	assert.Equal(t, []string{"before run", "handler 3 [a b] s3cret"}, calls)
+}
+
+func TestRunArgsErrors(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	assert.ErrorIs(t, sage.RunArgs(context.Background(), nil), usage.ErrNoHandler)
+
+	called := false
+	sage = usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	sage.Handle(func(context.Context) error {
+		called = true
+		return nil
+	})
+	err := sage.RunArgs(context.Background(), []string{"--unknown"})
+	var usageErr *usage.UsageError
+	assert.ErrorAs(t, err, &usageErr)
+	assert.False(t, called)
+
+	failure := errors.New("failure")
+	sage = usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	sage.Handle(func(context.Context) error { return failure })
+	assert.Equal(t, failure, sage.RunArgs(context.Background(), nil))
+}
+
+func TestValueFromContextWithoutUsage(t *testing.T) {
+	_, ok := usage.FromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = usage.ValueFromContext[int](context.Background(), "count")
+	assert.False(t, ok)
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, usage.EXIT_OK},
+		{"help", usage.ErrHelp, usage.EXIT_OK},
+		{"version", usage.ErrVersion, usage.EXIT_OK},
+		{"usage error", &usage.UsageError{Message: "bad"}, usage.EXIT_USAGE},
+		{"error list", usage.ErrorList{&usage.UsageError{}, &usage.UsageError{}}, usage.EXIT_USAGE},
+		{"interrupted", fmt.Errorf("%w: stopped", usage.ErrInterrupted), usage.EXIT_INTERRUPTED},
+		{"exit error", fmt.Errorf("wrapped: %w", &usage.ExitError{Code: 3}), 3},
+		{"other", errors.New("failure"), usage.EXIT_FAILURE},
+		{"flag help", flag.ErrHelp, usage.EXIT_OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, usage.ExitCode(tt.err))
+		})
+	}
+}
+
+func TestExitError(t *testing.T) {
+	cause := errors.New("not found")
+	err := &usage.ExitError{Code: 4, Err: cause}
+	assert.Equal(t, "not found", err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "exit status 5", (&usage.ExitError{Code: 5}).Error())
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package usage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunArgsInterrupted(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+	)
+	sage.Handle(func(ctx context.Context) error {
+		assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("context not cancelled")
+		}
+	})
+
+	err := sage.RunArgs(context.Background(), nil)
+	assert.ErrorIs(t, err, usage.ErrInterrupted)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, usage.EXIT_INTERRUPTED, usage.ExitCode(err))
+	assert.Equal(t, "interrupted by interrupt: context canceled", err.Error())
+}
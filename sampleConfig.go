@@ -0,0 +1,91 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+const (
+	// GENERATE_CONFIG_FLAG is the name of the option that prints a sample
+	// configuration, see WithGenerateConfigFlag.
+	GENERATE_CONFIG_FLAG = "generate-config"
+
+	// CONFIG_FORMAT_INI writes an INI file as read by WithConfigFiles.
+	CONFIG_FORMAT_INI = internal.ConfigFormatINI
+
+	// CONFIG_FORMAT_YAML writes a YAML document.
+	CONFIG_FORMAT_YAML = internal.ConfigFormatYAML
+
+	// CONFIG_FORMAT_TOML writes a TOML document.
+	CONFIG_FORMAT_TOML = internal.ConfigFormatTOML
+
+	// CONFIG_FORMAT_JSON writes a JSON object, without the comments.
+	CONFIG_FORMAT_JSON = internal.ConfigFormatJSON
+)
+
+// ConfigFormat selects the file format of a sample configuration, see
+// SampleConfig.
+type ConfigFormat = internal.ConfigFormat
+
+// WithGenerateConfigFlag adds the --generate-config option, which prints a
+// sample configuration file, see SampleConfig, and exits. The format is INI
+// unless another one is given, e.g. --generate-config=yaml. ParseArgs returns
+// ErrGenerateConfig when the option is used.
+func WithGenerateConfigFlag() UsageOption {
+	return func(u *Usage) {
+		u.generateConfigEnabled = true
+	}
+}
+
+// SampleConfig returns a sample configuration file in the given format with a
+// setting for each option, set to its default value. The settings are grouped
+// like the usage output, with the name and description of each group and the
+// description, type, default value, allowed values and environment variable
+// of each option as comments, except in JSON which has no comments. Hidden,
+// deprecated and secret options are left out, as are the built-in version and
+// --generate-config options. Returns an error if the format is not one of
+// CONFIG_FORMAT_INI, CONFIG_FORMAT_YAML, CONFIG_FORMAT_TOML or
+// CONFIG_FORMAT_JSON.
+func (s *Usage) SampleConfig(format ConfigFormat) (string, error) {
+	s.prepareUsage()
+	exclude := make(map[*internal.Option]bool)
+	for _, option := range s.configuration.Options() {
+		if f := s.flagSet.Lookup(option.Name()); f != nil {
+			switch f.Value.(type) {
+			case *internal.VersionFlag, *internal.ConfigFormatFlag:
+				exclude[option] = true
+			}
+		}
+	}
+	return internal.SampleConfig(s.configuration, format, exclude)
+}
+
+// PrintSampleConfig prints a sample configuration file to os.Stdout in the
+// format requested with the --generate-config option, INI by default, and
+// calls os.Exit(0).
+func (s *Usage) PrintSampleConfig() {
+	format := CONFIG_FORMAT_INI
+	if s.generateConfig != nil && s.generateConfig.Requested {
+		format = s.generateConfig.Format
+	}
+	sample, err := s.SampleConfig(format)
+	if err != nil {
+		s.PrintError(err)
+	}
+	fmt.Fprint(os.Stdout, sample)
+	os.Exit(0)
+}
+
+// registerGenerateConfigFlag registers the --generate-config option, unless
+// it is disabled or its name is already taken.
+func (s *Usage) registerGenerateConfigFlag() {
+	if !s.generateConfigEnabled || s.generateConfig != nil || s.flagSet.Lookup(GENERATE_CONFIG_FLAG) != nil {
+		return
+	}
+	s.generateConfig = &internal.ConfigFormatFlag{}
+	description := "Print a sample configuration file (use =yaml, =toml or =json for other formats)"
+	s.flagSet.Var(s.generateConfig, GENERATE_CONFIG_FLAG, description)
+	s.addOption("", GENERATE_CONFIG_FLAG, false, description, "", nil)
+}
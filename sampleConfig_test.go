@@ -0,0 +1,93 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// addSampleConfigOptions adds the options the sample configuration tests use
+// to sage.
+func addSampleConfigOptions(t *testing.T, sage *usage.Usage) {
+	t.Helper()
+	network := sage.AddGroup(1, "Network", "Connection settings")
+	_, err := sage.Int("retries").Default(3).Help("Retries").Env("APP_RETRIES").In(network).Build()
+	assert.NoError(t, err)
+	_, err = sage.String("token").Help("API token").Secret().Build()
+	assert.NoError(t, err)
+}
+
+func TestSampleConfig(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithGenerateConfigFlag(),
+	)
+	addSampleConfigOptions(t, sage)
+	assert.ErrorIs(t, sage.ParseArgs([]string{"--generate-config=toml"}), usage.ErrGenerateConfig)
+
+	sample, err := sage.SampleConfig(usage.CONFIG_FORMAT_INI)
+	assert.NoError(t, err)
+	assert.Equal(t, `# Configuration for app
+
+# Default
+# Default Options
+
+# Read --token from a file
+# Type: string
+# token-file =
+
+# Network
+# Connection settings
+
+# Retries
+# Type: int, Default: 3
+# Environment variable: APP_RETRIES
+retries = 3
+`, sample)
+}
+
+func TestSampleConfigLoads(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithGenerateConfigFlag(),
+	)
+	addSampleConfigOptions(t, sage)
+	sample, err := sage.SampleConfig(usage.CONFIG_FORMAT_INI)
+	assert.NoError(t, err)
+
+	loaded := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithConfigFiles(writeConfigFile(t, sample)),
+	)
+	retries, err := loaded.AddIntegerOptionE("", "retries", 0, "Retries", "", nil)
+	assert.NoError(t, err)
+	_, err = loaded.AddStringOptionE("", "token", "", "API token", "", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, loaded.ParseArgs(nil))
+	assert.Equal(t, 3, *retries)
+}
+
+func TestGenerateConfigExitCode(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("app"),
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithFormatter(pkg.NewStandardFormatter(&bytes.Buffer{}, &bytes.Buffer{}, nil)),
+		usage.WithGenerateConfigFlag(),
+	)
+	addSampleConfigOptions(t, sage)
+	err := sage.ParseArgs([]string{"--generate-config"})
+	assert.ErrorIs(t, err, usage.ErrGenerateConfig)
+	assert.Equal(t, usage.EXIT_OK, usage.ExitCode(err))
+
+	var usageErr *usage.UsageError
+	assert.ErrorAs(t, sage.ParseArgs([]string{"--generate-config=xml"}), &usageErr)
+}
@@ -0,0 +1,164 @@
+package usage
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bgrewell/usage/internal"
+)
+
+// WithInput sets the reader used for values read from standard input, such
+// as the value of a secret option given as "-". It defaults to os.Stdin.
+func WithInput(input io.Reader) UsageOption {
+	return func(u *Usage) {
+		u.input = input
+	}
+}
+
+// SetOptionSecret marks the option with the given short or long name as
+// secret. The value of a secret option is masked in the usage output, in
+// error messages and in Dump, and is read without echo when prompted for.
+// Its value can also be read from standard input by passing "-", or from a
+// file with the companion option named after the long name followed by
+// SECRET_FILE_SUFFIX, e.g. --token-file. Returns ErrOptionNotFound if no such
+// option has been added.
+func (s *Usage) SetOptionSecret(name string) error {
+	option, _ := s.configuration.FindOption(name)
+	if option == nil {
+		return fmt.Errorf("%w: %s", ErrOptionNotFound, name)
+	}
+	option.Secret = true
+	return nil
+}
+
+// Dump writes the current value of every option to w, one "--name=value" line
+// per option in display order, with the values of secret options masked. It
+// is meant for debug output and logs.
+func (s *Usage) Dump(w io.Writer) {
+	for _, option := range s.configuration.Options() {
+		fmt.Fprintf(w, "%s=%s\n", internal.Dashed(option.Name()), option.DisplayValue(s.optionValue(option)))
+	}
+}
+
+// prepareSecretOptions registers the companion file option of every secret
+// option with a long name, unless that name is already taken, and makes the
+// flags of secret options read their value from the input when given "-".
+func (s *Usage) prepareSecretOptions() {
+	for _, group := range s.configuration.SortedGroups() {
+		for _, option := range group.Options {
+			if !option.Secret {
+				continue
+			}
+			for _, name := range option.Names() {
+				if f := s.flagSet.Lookup(name); f != nil {
+					if _, ok := f.Value.(*stdinValue); !ok {
+						f.Value = &stdinValue{Value: f.Value, usage: s}
+					}
+				}
+			}
+
+			if option.Long == "" || s.secretFiles[option] != nil {
+				continue
+			}
+			name := option.Long + SECRET_FILE_SUFFIX
+			if s.flagSet.Lookup(name) != nil {
+				continue
+			}
+			var path string
+			description := fmt.Sprintf("Read %s from a file", internal.Dashed(option.Long))
+			s.flagSet.StringVar(&path, name, "", description)
+			group.AddOption(&internal.Option{
+				Long:        name,
+				Default:     "",
+				Description: description,
+				Hidden:      option.Hidden,
+				Advanced:    option.Advanced,
+			})
+			if s.secretFiles == nil {
+				s.secretFiles = make(map[*internal.Option]*string)
+			}
+			s.secretFiles[option] = &path
+		}
+	}
+}
+
+// readSecretFiles sets secret options from the files given with their
+// companion file options. A single trailing line break is removed.
+func (s *Usage) readSecretFiles() error {
+	for _, option := range s.configuration.Options() {
+		path := s.secretFiles[option]
+		if path == nil || *path == "" {
+			continue
+		}
+		data, err := os.ReadFile(*path)
+		if err != nil {
+			return &internal.UsageError{
+				Kind:    internal.ErrorKindInvalidValue,
+				Option:  option.Long + SECRET_FILE_SUFFIX,
+				Message: fmt.Sprintf("cannot read %s: %v", internal.Dashed(option.Long), err),
+				Err:     err,
+			}
+		}
+		if err := s.setValue(option, option.Name(), trimLineBreak(string(data))); err != nil {
+			err.Source = *path
+			return err
+		}
+	}
+	return nil
+}
+
+// readInput reads the whole input for a value given as "-". The input can
+// only be read once per parse.
+func (s *Usage) readInput() (string, error) {
+	if s.inputRead {
+		return "", errors.New("standard input was already read for another option")
+	}
+	s.inputRead = true
+	data, err := io.ReadAll(s.input)
+	if err != nil {
+		return "", err
+	}
+	return trimLineBreak(string(data)), nil
+}
+
+// trimLineBreak removes a single trailing line break from value.
+func trimLineBreak(value string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(value, "\n"), "\r")
+}
+
+// stdinValue wraps the flag.Value of a secret option so that the value "-"
+// is replaced by the contents of the input.
+type stdinValue struct {
+	flag.Value
+	usage *Usage
+}
+
+func (v *stdinValue) Set(value string) error {
+	if value == "-" {
+		input, err := v.usage.readInput()
+		if err != nil {
+			return err
+		}
+		value = input
+	}
+	return v.Value.Set(value)
+}
+
+// IsBoolFlag reports whether the wrapped value is a boolean flag.
+func (v *stdinValue) IsBoolFlag() bool {
+	b, ok := v.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// Get returns the value of the wrapped value if it implements flag.Getter,
+// otherwise its string form.
+func (v *stdinValue) Get() interface{} {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.Value.String()
+}
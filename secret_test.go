@@ -0,0 +1,117 @@
+package usage_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// addSecretOptions adds the secret token and pin options to sage.
+func addSecretOptions(t *testing.T, sage *usage.Usage) (*string, *int) {
+	t.Helper()
+	token, err := sage.AddStringOptionE("", "token", "default-token", "API token", "", nil)
+	assert.NoError(t, err)
+	pin, err := sage.AddIntegerOptionE("", "pin", 0, "PIN", "", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, sage.SetOptionSecret("token"))
+	assert.NoError(t, sage.SetOptionSecret("pin"))
+	return token, pin
+}
+
+func TestSecretFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0600))
+
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	token, _ := addSecretOptions(t, sage)
+	assert.NoError(t, sage.ParseArgs([]string{"--token-file", path}))
+	assert.Equal(t, "from-file", *token)
+	assert.True(t, sage.IsSet("token"))
+}
+
+func TestSecretFromMissingFile(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addSecretOptions(t, sage)
+	err := sage.ParseArgs([]string{"--token-file", filepath.Join(t.TempDir(), "missing")})
+	var usageErr *usage.UsageError
+	if assert.ErrorAs(t, err, &usageErr) {
+		assert.Equal(t, internal.ErrorKindInvalidValue, usageErr.Kind)
+		assert.Equal(t, "token-file", usageErr.Option)
+	}
+}
+
+func TestSecretFromStdin(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithInput(strings.NewReader("from-stdin\r\n")),
+	)
+	token, _ := addSecretOptions(t, sage)
+	assert.NoError(t, sage.ParseArgs([]string{"--token", "-"}))
+	assert.Equal(t, "from-stdin", *token)
+
+	// Standard input can only provide the value of a single option
+	sage = usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+		usage.WithInput(strings.NewReader("1234")),
+	)
+	addSecretOptions(t, sage)
+	err := sage.ParseArgs([]string{"--token", "-", "--pin", "-"})
+	assert.ErrorContains(t, err, "standard input was already read")
+}
+
+func TestSecretMaskedInErrors(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addSecretOptions(t, sage)
+	err := sage.ParseArgs([]string{"--pin", "hunter2"})
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "hunter2")
+		assert.Contains(t, err.Error(), internal.SecretMask)
+	}
+
+	err = sage.Set("pin", "hunter2")
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "hunter2")
+	}
+}
+
+func TestSecretMaskedInDump(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithoutVersionFlag(),
+	)
+	addSecretOptions(t, sage)
+	_, err := sage.AddStringOptionE("", "user", "", "User name", "", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, sage.ParseArgs([]string{"--token", "hunter2", "--user", "ada"}))
+
+	var out bytes.Buffer
+	sage.Dump(&out)
+	assert.NotContains(t, out.String(), "hunter2")
+	assert.Contains(t, out.String(), "--token="+internal.SecretMask+"\n")
+	assert.Contains(t, out.String(), "--pin="+internal.SecretMask+"\n")
+	assert.Contains(t, out.String(), "--user=ada\n")
+	assert.Contains(t, out.String(), "--token-file=\n")
+}
+
+func TestSetOptionSecretNotFound(t *testing.T) {
+	sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+	assert.ErrorIs(t, sage.SetOptionSecret("missing"), usage.ErrOptionNotFound)
+}
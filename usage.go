@@ -3,26 +3,149 @@
 package usage
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"github.com/bgrewell/usage/internal"
 	"github.com/bgrewell/usage/pkg"
+	"io"
 	"log"
 	"os"
+	"runtime/debug"
+	"strings"
 )
 
 const (
 	// GROUP_DEFAULT is the name of the default group where options are placed
 	// when no explicit group is specified.
 	GROUP_DEFAULT = "Default"
+
+	// ENV_HELP_FORMAT is the suffix of the environment variable used to select the
+	// formatter at runtime. The full name is the upper-cased application name
+	// followed by this suffix, e.g. MYAPP_HELP_FORMAT=json.
+	ENV_HELP_FORMAT = "HELP_FORMAT"
+
+	// HELP_ALL_FLAG is the name of the flag that shows advanced and deprecated
+	// options in addition to the regular options. It is handled automatically
+	// unless an option with the same name has been added.
+	HELP_ALL_FLAG = "help-all"
+
+	// DEFAULT_SUGGESTION_DISTANCE is the maximum number of edits between an
+	// unknown option and a registered option for it to be suggested.
+	DEFAULT_SUGGESTION_DISTANCE = 2
+
+	// SECRET_FILE_SUFFIX is appended to the long name of secret options to
+	// form the name of the option that reads the value from a file, e.g.
+	// --token-file for --token.
+	SECRET_FILE_SUFFIX = "-file"
+
+	// VERSION_FLAG is the default long name of the built-in version option.
+	VERSION_FLAG = "version"
+
+	// VERSION_SHORT renders the version as a concise line such as "myapp 1.2.3".
+	VERSION_SHORT = internal.VersionFormatShort
+
+	// VERSION_DETAILED renders the version with build, commit and runtime details.
+	VERSION_DETAILED = internal.VersionFormatDetailed
+
+	// VERSION_JSON renders the version information as a JSON document.
+	VERSION_JSON = internal.VersionFormatJSON
+
+	// ERROR_MODE_CONCISE prints errors with suggestions, the usage line and a
+	// hint to run --help. This is the default.
+	ERROR_MODE_CONCISE = internal.ErrorModeConcise
+
+	// ERROR_MODE_FULL prints errors together with the complete usage information.
+	ERROR_MODE_FULL = internal.ErrorModeFull
+
+	// EXIT_OK is the exit code of a successful run, see ExitCode.
+	EXIT_OK = 0
+
+	// EXIT_FAILURE is the exit code of a run that failed with an error.
+	EXIT_FAILURE = 1
+
+	// EXIT_USAGE is the exit code for invalid command lines.
+	EXIT_USAGE = 2
+
+	// EXIT_INTERRUPTED is the exit code of a run that was interrupted by a
+	// signal, following the shell convention of 128 plus the signal number of
+	// SIGINT.
+	EXIT_INTERRUPTED = 130
+
+	// SECTION_TOP places a custom section directly after the usage line.
+	SECTION_TOP = internal.SectionTop
+
+	// SECTION_BEFORE_OPTIONS places a custom section after the description,
+	// before the options.
+	SECTION_BEFORE_OPTIONS = internal.SectionBeforeOptions
+
+	// SECTION_BOTTOM places a custom section at the end of the usage output,
+	// after the arguments and examples.
+	SECTION_BOTTOM = internal.SectionBottom
 )
 
 var (
 	// ErrGroupNotFound is returned when attempting to add an option to a non-existent group.
 	ErrGroupNotFound = errors.New("group does not exist")
+
+	// ErrOptionNotFound is returned when referring to an option name that has not been added.
+	ErrOptionNotFound = errors.New("option does not exist")
+
+	// ErrArgumentNotFound is returned when referring to an argument name that has not been added.
+	ErrArgumentNotFound = errors.New("argument does not exist")
+
+	// ErrDuplicateOption is returned when an option name is already in use.
+	ErrDuplicateOption = errors.New("option already exists")
+
+	// ErrInvalidOption is returned when an option declared with a builder is
+	// incomplete or invalid.
+	ErrInvalidOption = errors.New("invalid option")
+
+	// ErrInvalidBinding is returned by Bind when the target or one of its
+	// fields cannot be bound to options.
+	ErrInvalidBinding = errors.New("invalid binding")
+
+	// ErrHelp is returned by ParseArgs when help was requested with -h, --help
+	// or --help-all.
+	ErrHelp = flag.ErrHelp
+
+	// ErrVersion is returned by ParseArgs when the version was requested with
+	// the built-in version option.
+	ErrVersion = errors.New("version requested")
+
+	// ErrGenerateConfig is returned by ParseArgs when a sample configuration
+	// was requested with the --generate-config option.
+	ErrGenerateConfig = errors.New("sample configuration requested")
+
+	// ErrNoHandler is returned by Run when no handler has been registered
+	// with Handle.
+	ErrNoHandler = errors.New("no handler registered")
+
+	// ErrInvalidAlias is returned when a command alias has an invalid name or
+	// expansion.
+	ErrInvalidAlias = errors.New("invalid alias")
+
+	// ErrDuplicateAlias is returned when a command alias is already defined.
+	ErrDuplicateAlias = errors.New("alias already exists")
+
+	// ErrInterrupted is returned by Run when the handler failed after the
+	// context was cancelled by SIGINT or SIGTERM.
+	ErrInterrupted = errors.New("interrupted")
 )
 
+// VersionFormat selects how version information is rendered, see FormatVersion.
+type VersionFormat = internal.VersionFormat
+
+// ErrorMode selects how errors are presented, see WithErrorMode.
+type ErrorMode = internal.ErrorMode
+
+// UsageError describes a problem with the command line, such as an unknown
+// option or an invalid value. Errors returned by ParseArgs can be inspected
+// with errors.As to obtain the error kind and the offending option, or one of
+// the typed errors such as *InvalidValueError for the offending token.
+type UsageError = internal.UsageError
+
 // UsageOption is a functional option for configuring a Usage instance.
 // It follows the functional options pattern to provide flexible configuration.
 type UsageOption func(sage *Usage)
@@ -63,6 +186,19 @@ func WithApplicationBranch(branch string) UsageOption {
 	}
 }
 
+// WithBuildInfo fills the application version, commit hash, build date and
+// modified state from the build information embedded by the Go toolchain
+// (runtime/debug.ReadBuildInfo) when they have not been set explicitly. The
+// version comes from the main module version, the commit hash, build date and
+// modified state from the vcs.revision, vcs.time and vcs.modified settings.
+// Values set with WithApplicationVersion and friends always take precedence,
+// regardless of the order of the options, so ldflags injection keeps working.
+func WithBuildInfo() UsageOption {
+	return func(u *Usage) {
+		u.buildInfo = true
+	}
+}
+
 // WithApplicationDescription sets the application description displayed in usage output.
 func WithApplicationDescription(description string) UsageOption {
 	return func(u *Usage) {
@@ -70,15 +206,135 @@ func WithApplicationDescription(description string) UsageOption {
 	}
 }
 
-// WithFormatter sets a custom formatter for usage and error output.
+// WithFormatter sets a built-in formatter for usage and error output.
 // By default, a ColorFormatter is used. You can provide a StandardFormatter
-// or implement your own custom formatter using the internal.Formatter interface.
+// or a JSONFormatter, created with a nil configuration which is filled in by
+// NewUsage. To implement your own formatter use WithCustomFormatter.
+//
+// Users can override the formatter without code changes by setting the
+// <APP>_HELP_FORMAT environment variable to "color", "plain" or "json".
 func WithFormatter(formatter internal.Formatter) UsageOption {
 	return func(u *Usage) {
 		u.formatter = formatter
 	}
 }
 
+// WithErrorMode sets how PrintError presents errors. ERROR_MODE_CONCISE, the
+// default, prints the error, any suggestions, the usage line and a hint to run
+// --help, so that the error does not scroll away in applications with many
+// options. ERROR_MODE_FULL prints the complete usage information as well.
+// The JSON formatter is not affected.
+func WithErrorMode(mode ErrorMode) UsageOption {
+	return func(u *Usage) {
+		u.configuration.ErrorMode = mode
+	}
+}
+
+// WithTheme sets the color theme used by the ColorFormatter. Built-in themes are
+// available from the pkg package (e.g. pkg.NewLightTheme()) or a custom Theme
+// can be provided. The theme has no effect on formatters that do not use colors.
+func WithTheme(theme *internal.Theme) UsageOption {
+	return func(u *Usage) {
+		u.theme = theme
+	}
+}
+
+// WithFlagSet sets the flag.FlagSet that options are registered with and that
+// is used for parsing. By default the global flag.CommandLine is used.
+func WithFlagSet(fs *flag.FlagSet) UsageOption {
+	return func(u *Usage) {
+		u.flagSet = fs
+	}
+}
+
+// WithSuggestionDistance sets the maximum number of edits (insertions,
+// deletions, substitutions or transpositions) between an unknown option and a
+// registered option for the registered option to be suggested in the error,
+// e.g. "did you mean --timeout?", and likewise between an invalid value and
+// the choices of its option. The default is DEFAULT_SUGGESTION_DISTANCE.
+// A distance less than one disables suggestions.
+func WithSuggestionDistance(distance int) UsageOption {
+	return func(u *Usage) {
+		u.suggestionDistance = distance
+	}
+}
+
+// WithoutSuggestions disables the "did you mean" suggestions for unknown
+// options and invalid choices.
+func WithoutSuggestions() UsageOption {
+	return WithSuggestionDistance(0)
+}
+
+// WithAbbreviations enables abbreviated long options, so users can type an
+// unambiguous prefix of a long option name such as --verb for --verbose, as
+// supported by GNU getopt. An exact match always takes precedence over an
+// abbreviation, and an ambiguous prefix results in an error listing the
+// matching options. Hidden options must always be typed in full.
+func WithAbbreviations() UsageOption {
+	return func(u *Usage) {
+		u.abbreviations = true
+	}
+}
+
+// WithNegatedBooleans enables the negated form of boolean options, so users
+// can turn off a boolean option with a "no-" prefix, e.g. --no-color sets
+// --color to false. An option registered under the negated name, such as
+// no-color, takes precedence.
+func WithNegatedBooleans() UsageOption {
+	return func(u *Usage) {
+		u.negation = true
+	}
+}
+
+// WithResponseFiles enables response files. An argument of the form "@path" is
+// replaced by the arguments read from the file at path before any option is
+// parsed. Response files use shell-like quoting, support "#" comments and can
+// include other response files with "@path" (relative to the including file).
+// Use "@@value" to pass a literal argument starting with "@". Errors caused by
+// an argument read from a response file report the file and line.
+func WithResponseFiles() UsageOption {
+	return func(u *Usage) {
+		u.responseFiles = true
+	}
+}
+
+// WithVersionFlag sets the short and long names of the built-in version option.
+// Either name may be empty. By default only the long name VERSION_FLAG is used.
+// Names that are already used by another option are skipped.
+func WithVersionFlag(short string, long string) UsageOption {
+	return func(u *Usage) {
+		u.versionShort = short
+		u.versionLong = long
+	}
+}
+
+// WithoutVersionFlag disables the built-in version option.
+func WithoutVersionFlag() UsageOption {
+	return WithVersionFlag("", "")
+}
+
+// WithVersionTemplate sets the text/template used for the concise version
+// output. The template is executed with the fields Name, Version, BuildDate,
+// CommitHash, Branch, Modified, GoVersion, OS and Arch.
+//
+// Example:
+//
+//	usage.WithVersionTemplate("{{.Name}} version {{.Version}} ({{.CommitHash}})")
+func WithVersionTemplate(template string) UsageOption {
+	return func(u *Usage) {
+		u.versionTemplate = template
+	}
+}
+
+// WithDetailedVersionTemplate sets the text/template used for the detailed
+// version output shown with --version=detailed. The template is executed with
+// the same fields as WithVersionTemplate.
+func WithDetailedVersionTemplate(template string) UsageOption {
+	return func(u *Usage) {
+		u.detailedVersionTemplate = template
+	}
+}
+
 // NewUsage creates a new Usage instance with the provided functional options.
 // By default, it automatically detects the executable name, uses a ColorFormatter,
 // and creates a default option group. The function also sets flag.Usage to
@@ -101,10 +357,29 @@ func NewUsage(options ...UsageOption) *Usage {
 	u := &Usage{
 		configuration: c,
 		formatter:     pkg.NewColorFormatter(os.Stdout, os.Stderr, c),
+		flagSet:       flag.CommandLine,
+		input:         os.Stdin,
+
+		suggestionDistance: DEFAULT_SUGGESTION_DISTANCE,
+		versionLong:        VERSION_FLAG,
 	}
 	for _, opt := range options {
 		opt(u)
 	}
+	if u.buildInfo {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			internal.ApplyBuildInfo(c, info)
+		}
+	}
+	if name := os.Getenv(internal.EnvVarName(c.ApplicationName, ENV_HELP_FORMAT)); name != "" {
+		if f, ok := pkg.NewFormatterByName(name, os.Stdout, os.Stderr, c); ok {
+			u.formatter = f
+		}
+	}
+	attachConfiguration(u.formatter, c)
+	if cf, ok := u.formatter.(*internal.ColorFormatter); ok && u.theme != nil {
+		cf.Theme = u.theme
+	}
 	flag.Usage = u.PrintUsage
 	return u
 }
@@ -115,7 +390,53 @@ func NewUsage(options ...UsageOption) *Usage {
 type Usage struct {
 	configuration *internal.Configuration
 	formatter     internal.Formatter
+	theme         *internal.Theme
+	flagSet       *flag.FlagSet
 	arguments     []*string
+	argumentDefs  []*internal.Argument
+	buildInfo     bool
+
+	declarationErrors []error
+
+	suggestionDistance int
+	abbreviations      bool
+	negation           bool
+	responseFiles      bool
+	prompts            bool
+	prompter           *internal.Prompter
+	input              io.Reader
+	inputRead          bool
+	secretFiles        map[*internal.Option]*string
+
+	parent  *Usage
+	hooks   hooks
+	handler func(ctx context.Context) error
+
+	pluginsEnabled bool
+	pluginDirs     []string
+	plugins        []internal.Plugin
+	pluginCall     *pluginCall
+
+	configFiles  []string
+	configLayers [][]internal.IniEntry
+	configLoaded bool
+	configErr    error
+	aliases      map[string]*internal.CommandAlias
+	aliasSection *internal.Section
+
+	profilesEnabled bool
+	profile         string
+	profileOption   *internal.Option
+	profileSection  *internal.Section
+
+	generateConfigEnabled bool
+	generateConfig        *internal.ConfigFormatFlag
+
+	versionShort            string
+	versionLong             string
+	versionTemplate         string
+	detailedVersionTemplate string
+	versionFlag             *internal.VersionFlag
 }
 
 // ApplicationName returns the configured application name.
@@ -143,6 +464,12 @@ func (s *Usage) ApplicationBranch() string {
 	return s.configuration.ApplicationBranch
 }
 
+// ApplicationModified reports whether the application was built from a
+// working tree with uncommitted changes, as detected by WithBuildInfo.
+func (s *Usage) ApplicationModified() bool {
+	return s.configuration.ApplicationModified
+}
+
 // ApplicationDescription returns the configured application description.
 func (s *Usage) ApplicationDescription() string {
 	return s.configuration.ApplicationDescription
@@ -158,23 +485,97 @@ func (s *Usage) AddGroup(priority int, name string, description string) *interna
 	return group
 }
 
+// HideOption hides the option with the given short or long name from the usage
+// output. The option is still parsed, which makes it suitable for internal
+// debugging flags. Returns ErrOptionNotFound if no such option has been added.
+func (s *Usage) HideOption(name string) error {
+	option, _ := s.configuration.FindOption(name)
+	if option == nil {
+		return fmt.Errorf("%w: %s", ErrOptionNotFound, name)
+	}
+	option.Hidden = true
+	return nil
+}
+
+// SetOptionAdvanced marks the option with the given short or long name as
+// advanced. Advanced options are only shown in the usage output when the user
+// requests all options with --help-all. Returns ErrOptionNotFound if no such
+// option has been added.
+func (s *Usage) SetOptionAdvanced(name string) error {
+	option, _ := s.configuration.FindOption(name)
+	if option == nil {
+		return fmt.Errorf("%w: %s", ErrOptionNotFound, name)
+	}
+	option.Advanced = true
+	return nil
+}
+
+// DeprecateOption marks the option with the given short or long name as
+// deprecated. The option keeps working but a warning containing the message
+// and the replacement option (if not empty) is printed through the formatter
+// when it is used. Deprecated options are only shown with --help-all.
+// Returns ErrOptionNotFound if no such option has been added.
+func (s *Usage) DeprecateOption(name string, message string, replacement string) error {
+	option, _ := s.configuration.FindOption(name)
+	if option == nil {
+		return fmt.Errorf("%w: %s", ErrOptionNotFound, name)
+	}
+	if message == "" {
+		message = "it will be removed in a future release"
+	}
+	option.Deprecated = message
+	option.Replacement = replacement
+	return nil
+}
+
+// AddExample adds an example invocation to the "Examples" section of the usage
+// output. The command is shown as written and the explanation is displayed
+// below it. Examples are shown in the order they are added.
+//
+// Example:
+//
+//	u.AddExample("myapp -t 5 https://example.com", "Fetch a page with a 5 second timeout")
+func (s *Usage) AddExample(command string, explanation string) {
+	s.configuration.Examples = append(s.configuration.Examples, &internal.Example{
+		Command:     command,
+		Explanation: explanation,
+	})
+}
+
+// AddSection adds a free-form section to the usage output, such as "See also",
+// environment notes, a bug-report URL or copyright information. Line breaks in
+// the body are preserved. The position is one of SECTION_TOP,
+// SECTION_BEFORE_OPTIONS or SECTION_BOTTOM; sections sharing a position are
+// shown in the order they are added.
+func (s *Usage) AddSection(title string, body string, position internal.SectionPosition) {
+	s.configuration.Sections = append(s.configuration.Sections, &internal.Section{
+		Title:    title,
+		Body:     body,
+		Position: position,
+	})
+}
+
 // addOptionE adds an option to a group and returns an error if the group doesn't exist.
 // This is the error-returning version of addOption.
 func (s *Usage) addOptionE(short string, long string, defaultValue interface{}, description string, extra string, group *internal.Group) error {
-	o := internal.Option{
+	return s.addOptionToGroup(&internal.Option{
 		Short:       short,
 		Long:        long,
 		Default:     defaultValue,
 		Description: description,
 		Extra:       extra,
-	}
+	}, group)
+}
 
+// addOptionToGroup adds a fully configured option to a group, or to the default
+// group if group is nil, and returns an error if the group doesn't exist.
+func (s *Usage) addOptionToGroup(option *internal.Option, group *internal.Group) error {
 	if group == nil {
 		group = s.configuration.Groups[GROUP_DEFAULT]
 	}
 
 	if g, ok := s.configuration.Groups[group.Name]; ok {
-		g.AddOption(&o)
+		g.AddOption(option)
 		return nil
 	}
 	return fmt.Errorf("%w: %s", ErrGroupNotFound, group.Name)
@@ -203,10 +604,10 @@ func (s *Usage) addOption(short string, long string, defaultValue interface{}, d
 func (s *Usage) AddBooleanOption(short string, long string, defaultValue bool, description string, extra string, group *internal.Group) *bool {
 	var flagBool bool
 	if short != "" {
-		flag.BoolVar(&flagBool, short, defaultValue, description)
+		s.flagSet.BoolVar(&flagBool, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.BoolVar(&flagBool, long, defaultValue, description)
+		s.flagSet.BoolVar(&flagBool, long, defaultValue, description)
 	}
 	s.addOption(short, long, defaultValue, description, extra, group)
 	return &flagBool
@@ -226,10 +627,10 @@ func (s *Usage) AddBooleanOption(short string, long string, defaultValue bool, d
 func (s *Usage) AddIntegerOption(short string, long string, defaultValue int, description string, extra string, group *internal.Group) *int {
 	var flagInt int
 	if short != "" {
-		flag.IntVar(&flagInt, short, defaultValue, description)
+		s.flagSet.IntVar(&flagInt, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.IntVar(&flagInt, long, defaultValue, description)
+		s.flagSet.IntVar(&flagInt, long, defaultValue, description)
 	}
 	s.addOption(short, long, defaultValue, description, extra, group)
 	return &flagInt
@@ -249,10 +650,10 @@ func (s *Usage) AddIntegerOption(short string, long string, defaultValue int, de
 func (s *Usage) AddFloatOption(short string, long string, defaultValue float64, description string, extra string, group *internal.Group) *float64 {
 	var flagFloat float64
 	if short != "" {
-		flag.Float64Var(&flagFloat, short, defaultValue, description)
+		s.flagSet.Float64Var(&flagFloat, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.Float64Var(&flagFloat, long, defaultValue, description)
+		s.flagSet.Float64Var(&flagFloat, long, defaultValue, description)
 	}
 	s.addOption(short, long, defaultValue, description, extra, group)
 	return &flagFloat
@@ -272,10 +673,10 @@ func (s *Usage) AddFloatOption(short string, long string, defaultValue float64,
 func (s *Usage) AddStringOption(short string, long string, defaultValue string, description string, extra string, group *internal.Group) *string {
 	var flagString string
 	if short != "" {
-		flag.StringVar(&flagString, short, defaultValue, description)
+		s.flagSet.StringVar(&flagString, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.StringVar(&flagString, long, defaultValue, description)
+		s.flagSet.StringVar(&flagString, long, defaultValue, description)
 	}
 	s.addOption(short, long, defaultValue, description, extra, group)
 	return &flagString
@@ -287,10 +688,10 @@ func (s *Usage) AddStringOption(short string, long string, defaultValue string,
 func (s *Usage) AddBooleanOptionE(short string, long string, defaultValue bool, description string, extra string, group *internal.Group) (*bool, error) {
 	var flagBool bool
 	if short != "" {
-		flag.BoolVar(&flagBool, short, defaultValue, description)
+		s.flagSet.BoolVar(&flagBool, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.BoolVar(&flagBool, long, defaultValue, description)
+		s.flagSet.BoolVar(&flagBool, long, defaultValue, description)
 	}
 	if err := s.addOptionE(short, long, defaultValue, description, extra, group); err != nil {
 		return nil, err
@@ -304,10 +705,10 @@ func (s *Usage) AddBooleanOptionE(short string, long string, defaultValue bool,
 func (s *Usage) AddIntegerOptionE(short string, long string, defaultValue int, description string, extra string, group *internal.Group) (*int, error) {
 	var flagInt int
 	if short != "" {
-		flag.IntVar(&flagInt, short, defaultValue, description)
+		s.flagSet.IntVar(&flagInt, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.IntVar(&flagInt, long, defaultValue, description)
+		s.flagSet.IntVar(&flagInt, long, defaultValue, description)
 	}
 	if err := s.addOptionE(short, long, defaultValue, description, extra, group); err != nil {
 		return nil, err
@@ -321,10 +722,10 @@ func (s *Usage) AddIntegerOptionE(short string, long string, defaultValue int, d
 func (s *Usage) AddFloatOptionE(short string, long string, defaultValue float64, description string, extra string, group *internal.Group) (*float64, error) {
 	var flagFloat float64
 	if short != "" {
-		flag.Float64Var(&flagFloat, short, defaultValue, description)
+		s.flagSet.Float64Var(&flagFloat, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.Float64Var(&flagFloat, long, defaultValue, description)
+		s.flagSet.Float64Var(&flagFloat, long, defaultValue, description)
 	}
 	if err := s.addOptionE(short, long, defaultValue, description, extra, group); err != nil {
 		return nil, err
@@ -338,10 +739,10 @@ func (s *Usage) AddFloatOptionE(short string, long string, defaultValue float64,
 func (s *Usage) AddStringOptionE(short string, long string, defaultValue string, description string, extra string, group *internal.Group) (*string, error) {
 	var flagString string
 	if short != "" {
-		flag.StringVar(&flagString, short, defaultValue, description)
+		s.flagSet.StringVar(&flagString, short, defaultValue, description)
 	}
 	if long != "" {
-		flag.StringVar(&flagString, long, defaultValue, description)
+		s.flagSet.StringVar(&flagString, long, defaultValue, description)
 	}
 	if err := s.addOptionE(short, long, defaultValue, description, extra, group); err != nil {
 		return nil, err
@@ -371,20 +772,230 @@ func (s *Usage) AddArgument(position int, name string, description string, extra
 		Name:        name,
 		Description: description,
 	}
+	s.argumentDefs = append(s.argumentDefs, &a)
 	s.configuration.Groups[GROUP_DEFAULT].AddArgument(&a)
 
 	return &argString
 }
 
-// Parse wraps flag.Parse() and populates the positional arguments.
-// This method should be called after all options and arguments have been added.
-// The last declared argument will accumulate all remaining command-line arguments.
+// RequireArgument marks the positional argument with the given name as
+// required. Parsing fails when it is not provided, unless it can be prompted
+// for. Returns ErrArgumentNotFound if no such argument has been added.
+func (s *Usage) RequireArgument(name string) error {
+	for _, argument := range s.argumentDefs {
+		if argument.Name == name {
+			argument.Required = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrArgumentNotFound, name)
+}
+
+// registerVersionFlag registers the built-in version option the first time
+// arguments are parsed, unless it is disabled or its names are already taken.
+func (s *Usage) registerVersionFlag() {
+	if s.versionFlag != nil {
+		return
+	}
+	short, long := s.versionShort, s.versionLong
+	if short != "" && s.flagSet.Lookup(short) != nil {
+		short = ""
+	}
+	if long != "" && s.flagSet.Lookup(long) != nil {
+		long = ""
+	}
+	if short == "" && long == "" {
+		return
+	}
+
+	s.versionFlag = &internal.VersionFlag{}
+	description := "Show version information (use =detailed or =json for more)"
+	for _, name := range []string{short, long} {
+		if name != "" {
+			s.flagSet.Var(s.versionFlag, name, description)
+		}
+	}
+	s.addOption(short, long, false, description, "", nil)
+}
+
+// FormatVersion returns the version information of the application rendered
+// in the given format, using the configured version templates.
+func (s *Usage) FormatVersion(format VersionFormat) (string, error) {
+	return internal.NewVersionInfo(s.configuration).Render(format, s.versionTemplate, s.detailedVersionTemplate)
+}
+
+// PrintVersion prints the version information to os.Stdout in the format
+// requested with the version option (the concise line by default, or JSON
+// when the JSON formatter is active) and calls os.Exit(0).
+func (s *Usage) PrintVersion() {
+	format := VERSION_SHORT
+	if s.versionFlag != nil && s.versionFlag.Requested {
+		format = s.versionFlag.Format
+	}
+	if _, ok := s.formatter.(*internal.JSONFormatter); ok && format == VERSION_SHORT {
+		format = VERSION_JSON
+	}
+
+	version, err := s.FormatVersion(format)
+	if err != nil {
+		s.PrintError(err)
+	}
+	fmt.Fprint(os.Stdout, version)
+	os.Exit(0)
+}
+
+// Parse parses the command-line arguments from os.Args and populates the
+// positional arguments. This method should be called after all options and
+// arguments have been added. If help is requested the usage is printed and
+// the program exits, and if parsing fails the error is printed through the
+// formatter and the program exits with a non-zero status. If the command
+// line names a plugin command, see WithPlugins, the plugin is run and the
+// program exits with its exit code.
 // Returns true if parsing was successful (same as flag.Parsed()).
 func (s *Usage) Parse() bool {
-	flag.Parse()
+	if err := s.ParseArgs(os.Args[1:]); err != nil {
+		if errors.Is(err, ErrHelp) {
+			s.PrintUsage()
+		}
+		if errors.Is(err, ErrVersion) {
+			s.PrintVersion()
+		}
+		if errors.Is(err, ErrGenerateConfig) {
+			s.PrintSampleConfig()
+		}
+		s.PrintError(err)
+	}
+	if s.pluginCall != nil {
+		s.exit(s.invoke(context.Background(), s.runPlugin))
+	}
+	return s.flagSet.Parsed()
+}
+
+// ParseArgs parses the provided arguments, which should not include the
+// program name, and populates the positional arguments. The last declared
+// argument will accumulate all remaining command-line arguments.
+//
+// Unlike Parse, ParseArgs never exits the program or prints anything other
+// than deprecation warnings. It returns ErrHelp if help was requested,
+// ErrVersion if the version was requested, ErrGenerateConfig if a sample
+// configuration was requested and a *UsageError if the arguments
+// are invalid. Invalid choices and missing required values are all reported
+// together in an ErrorList when there is more than one. Errors in options
+// declared with OptionBuilder.Var are returned before any argument is parsed.
+// Errors returned by the hooks added with OnBeforeParse, OnSourceApplied and
+// OnValidated abort parsing and are returned as they are.
+func (s *Usage) ParseArgs(args []string) error {
+	if len(s.declarationErrors) > 0 {
+		return s.declarationErrors[0]
+	}
+	if err := s.runBeforeParse(args); err != nil {
+		return err
+	}
+	s.registerVersionFlag()
+	s.registerProfileFlag()
+	s.registerGenerateConfigFlag()
+	s.prepareSecretOptions()
+	s.inputRead = false
+	s.pluginCall = nil
+	s.profile = ""
+
+	// Take over error handling and output from the flag set for the duration
+	// of the parse, so errors are reported through the formatter instead.
+	fs := s.flagSet
+	handling, output, usage := fs.ErrorHandling(), fs.Output(), fs.Usage
+	fs.Init(fs.Name(), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	defer func() {
+		fs.Init(fs.Name(), handling)
+		fs.SetOutput(output)
+		fs.Usage = usage
+	}()
+
+	// Expand response files before anything else so all arguments are known
+	tokens := internal.NewTokens(args)
+	if s.responseFiles {
+		expander := &internal.ResponseFileExpander{}
+		var err error
+		if tokens, err = expander.Expand(args); err != nil {
+			return err
+		}
+	}
+
+	// Expand command aliases, including those from the configuration files
+	if err := s.loadConfig(); err != nil {
+		return err
+	}
+	tokens, err := s.expandAliases(tokens)
+	if err != nil {
+		return err
+	}
+	args = internal.TokenValues(tokens)
+
+	// Handle the request for the complete help including advanced options
+	if fs.Lookup(HELP_ALL_FLAG) == nil {
+		for _, arg := range args {
+			if arg == "--" {
+				break
+			}
+			if arg == "-"+HELP_ALL_FLAG || arg == "--"+HELP_ALL_FLAG {
+				s.configuration.ShowAll = true
+				return ErrHelp
+			}
+		}
+	}
+
+	// Resolve negated boolean options and abbreviations to their full names
+	normalized, err := s.argNormalizer().Normalize(args)
+	if err != nil {
+		usageErr := internal.NewUsageError(err)
+		usageErr.Source = internal.SourceOf(tokens, usageErr.Option)
+		usageErr.Locate(s.configuration, args, -1)
+		return usageErr
+	}
+	for i := range tokens {
+		tokens[i].Value = normalized[i]
+	}
+
+	if err := fs.Parse(normalized); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ErrHelp
+		}
+		// The flag set stops at the offending token, which is the last one it consumed
+		usageErr := internal.NewUsageError(err)
+		usageErr.SuggestOptions(s.configuration, s.suggestionDistance)
+		usageErr.Source = internal.SourceOf(tokens, usageErr.Option)
+		if option := s.LookupOption(usageErr.Option); option != nil && option.Secret {
+			usageErr.MaskValue()
+		}
+		usageErr.Locate(s.configuration, args, len(normalized)-len(fs.Args())-1)
+		return usageErr
+	}
 
-	// Populate arguments
-	for i, arg := range flag.Args() {
+	if s.versionFlag != nil && s.versionFlag.Requested {
+		return ErrVersion
+	}
+	if s.generateConfig != nil && s.generateConfig.Requested {
+		return ErrGenerateConfig
+	}
+
+	// Warn about any deprecated options that were used
+	fs.Visit(func(f *flag.Flag) {
+		if warning, ok := s.configuration.DeprecationWarning(f.Name); ok {
+			s.printWarning(warning)
+		}
+	})
+
+	// Hand the remaining arguments to the plugin named by the first of them,
+	// otherwise populate the arguments
+	s.pluginCall = s.selectPlugin(fs.Args())
+	for i, arg := range fs.Args() {
+		if s.pluginCall != nil {
+			break
+		}
+		if len(s.arguments) == 0 {
+			break
+		}
 		// If this is the last argument in s.arguments then accumulate the rest of the arguments joined by a space
 		if i >= len(s.arguments) {
 			*s.arguments[len(s.arguments)-1] += " " + arg
@@ -393,20 +1004,260 @@ func (s *Usage) Parse() bool {
 		}
 	}
 
-	return flag.Parsed()
+	if err := s.runSourceApplied(SOURCE_COMMAND_LINE); err != nil {
+		return err
+	}
+
+	// Read secret values from files, then fill options that were not given
+	// from their environment variables
+	if err := s.readSecretFiles(); err != nil {
+		return err
+	}
+	if err := s.runSourceApplied(SOURCE_SECRET_FILES); err != nil {
+		return err
+	}
+	if err := s.applyEnvironment(); err != nil {
+		return err
+	}
+	if err := s.runSourceApplied(SOURCE_ENVIRONMENT); err != nil {
+		return err
+	}
+
+	// Fill in the options that are still not set from the selected profile
+	// and then from the configuration files
+	if s.profilesEnabled {
+		if err := s.applyProfile(args); err != nil {
+			return err
+		}
+		if err := s.runSourceApplied(SOURCE_PROFILE); err != nil {
+			return err
+		}
+	}
+	if len(s.configFiles) > 0 {
+		if err := s.applyConfigValues(); err != nil {
+			return err
+		}
+		if err := s.runSourceApplied(SOURCE_CONFIG_FILE); err != nil {
+			return err
+		}
+	}
+
+	// Ask for missing required values when running interactively, then make
+	// sure all values are allowed and all required values are present. The
+	// requirements do not apply to plugins.
+	if s.pluginCall == nil {
+		if err := s.promptMissing(); err != nil {
+			return err
+		}
+		if s.prompts {
+			if err := s.runSourceApplied(SOURCE_PROMPT); err != nil {
+				return err
+			}
+		}
+	}
+	errs := s.checkChoices(args)
+	if s.pluginCall == nil {
+		errs = append(errs, s.checkRequired(args)...)
+	}
+	for _, err := range errs {
+		err.Locate(s.configuration, args, -1)
+	}
+	if err := s.errorList(errs); err != nil {
+		return err
+	}
+	return s.runValidated()
+}
+
+// argNormalizer returns the normalizer that resolves negated boolean options
+// and abbreviations as configured.
+func (s *Usage) argNormalizer() *internal.ArgNormalizer {
+	return &internal.ArgNormalizer{
+		FlagSet:       s.flagSet,
+		Configuration: s.configuration,
+		Abbreviations: s.abbreviations,
+		Negation:      s.negation,
+	}
+}
+
+// errorList returns nil when there are no errors, the error itself when there
+// is one and an ErrorList otherwise.
+func (s *Usage) errorList(errs []*internal.UsageError) error {
+	list := make([]error, len(errs))
+	for i, err := range errs {
+		list[i] = err
+	}
+	return internal.NewErrorList(list)
+}
+
+// setOptions returns the options that have been set so far, on the command
+// line or by one of the sources applied after it.
+func (s *Usage) setOptions() map[*internal.Option]bool {
+	set := make(map[*internal.Option]bool)
+	s.flagSet.Visit(func(f *flag.Flag) {
+		if option, _ := s.configuration.FindOption(f.Name); option != nil {
+			set[option] = true
+		}
+	})
+	return set
+}
+
+// applyEnvironment sets the options that were not given on the command line
+// from their environment variables, or former environment variables, if
+// those are set.
+func (s *Usage) applyEnvironment() error {
+	set := s.setOptions()
+	for _, option := range s.configuration.Options() {
+		if set[option] {
+			continue
+		}
+		env, value, ok := s.environmentValue(option)
+		if !ok {
+			continue
+		}
+		if err := s.setValue(option, option.Name(), value); err != nil {
+			err.Source = "environment variable " + env
+			return err
+		}
+	}
+	return nil
+}
+
+// setValue sets the value of option, registered under name, after checking it
+// against the choices of the option.
+func (s *Usage) setValue(option *internal.Option, name string, value string) *internal.UsageError {
+	if err := s.flagSet.Set(name, value); err != nil {
+		return &internal.UsageError{
+			Kind:    internal.ErrorKindInvalidValue,
+			Option:  name,
+			Message: fmt.Sprintf("invalid value %q for flag %s: %v", option.DisplayValue(value), internal.Dashed(name), err),
+			Err: &internal.InvalidValueError{
+				Location: internal.NoLocation,
+				Option:   option,
+				Name:     name,
+				Value:    option.DisplayValue(value),
+				Err:      err,
+			},
+		}
+	}
+	if !option.AllowsValue(value) {
+		return s.choiceError(option, value, internal.NoLocation)
+	}
+	return nil
+}
+
+// choiceError returns the error for an option set to a value that is not one
+// of its choices.
+func (s *Usage) choiceError(option *internal.Option, value string, location internal.Location) *internal.UsageError {
+	constraint := fmt.Sprintf("must be one of %s", strings.Join(option.Choices, ", "))
+	usageErr := &internal.UsageError{
+		Kind:    internal.ErrorKindInvalidValue,
+		Option:  option.Name(),
+		Message: fmt.Sprintf("invalid value %q for flag %s: %s", option.DisplayValue(value), internal.Dashed(option.Name()), constraint),
+		Err: &internal.ConstraintError{
+			Location:   location,
+			Option:     option,
+			Value:      option.DisplayValue(value),
+			Constraint: constraint,
+		},
+	}
+	// Suggestions would reveal how close a secret value is to a choice
+	if !option.Secret {
+		usageErr.Suggestions = internal.Suggest(value, option.Choices, s.suggestionDistance)
+	}
+	return usageErr
+}
+
+// checkChoices returns an error for each option with choices that was set on
+// the command line, args, to a value that is not one of them.
+func (s *Usage) checkChoices(args []string) []*internal.UsageError {
+	var errs []*internal.UsageError
+	set := s.setOptions()
+	for _, option := range s.configuration.Options() {
+		if !set[option] {
+			continue
+		}
+		if value := s.optionValue(option); !option.AllowsValue(value) {
+			location := internal.FindToken(s.configuration, internal.MaskArgs(s.configuration, args), option)
+			errs = append(errs, s.choiceError(option, value, location))
+		}
+	}
+	return errs
+}
+
+// checkRequired returns an error for each required option, in the order of
+// the usage output, that was neither given on the command line nor through its
+// environment variable, followed by an error for each missing required
+// argument, located at the end of the command line args.
+func (s *Usage) checkRequired(args []string) []*internal.UsageError {
+	var errs []*internal.UsageError
+	set := s.setOptions()
+	for _, option := range s.configuration.Options() {
+		if option.Required && !set[option] {
+			message := fmt.Sprintf("missing required option %s", internal.Dashed(option.Name()))
+			if option.Env != "" {
+				message += fmt.Sprintf(" (or environment variable %s)", option.Env)
+			}
+			errs = append(errs, &internal.UsageError{
+				Kind:    internal.ErrorKindMissingOption,
+				Option:  option.Name(),
+				Message: message,
+				Err:     &internal.MissingOptionError{Location: internal.NoLocation, Option: option},
+			})
+		}
+	}
+	for i, argument := range s.argumentDefs {
+		if argument.Required && *s.arguments[i] == "" {
+			errs = append(errs, &internal.UsageError{
+				Kind:     internal.ErrorKindMissingArgument,
+				Argument: argument.Name,
+				Message:  fmt.Sprintf("missing required argument %s", argument.Name),
+				Err: &internal.MissingArgumentError{
+					Location: internal.Location{Index: len(args)},
+					Argument: argument,
+				},
+			})
+		}
+	}
+	return errs
 }
 
 // PrintUsage prints the usage information to the configured output writer
 // and calls os.Exit(0). This is automatically set as flag.Usage in NewUsage.
 func (s *Usage) PrintUsage() {
+	s.prepareUsage()
 	s.formatter.PrintUsage()
 	os.Exit(0)
 }
 
-// PrintError prints the error message and usage information to the configured
-// error writer and calls os.Exit(1). This is typically used when command-line
-// parsing or validation fails.
+// prepareUsage registers the built-in options that are otherwise registered
+// by ParseArgs and adds the sections listing the plugin commands, and the
+// aliases and profiles of the configuration files to the usage output, so
+// that usage printed before parsing is complete. Errors in the configuration
+// files are reported by ParseArgs.
+func (s *Usage) prepareUsage() {
+	s.loadPlugins()
+	_ = s.loadConfig()
+	s.registerVersionFlag()
+	s.registerProfileFlag()
+	s.registerGenerateConfigFlag()
+	s.prepareSecretOptions()
+}
+
+// printWarning prints a warning through the formatter, or to os.Stderr if
+// the formatter does not print warnings.
+func (s *Usage) printWarning(message string) {
+	if printer, ok := s.formatter.(internal.WarningPrinter); ok {
+		printer.PrintWarning(message)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", message)
+}
+
+// PrintError prints the error message to the configured error writer, as
+// selected with WithErrorMode, and calls os.Exit with the code returned by
+// ExitCode, like Execute. This is typically used when command-line parsing
+// or validation fails.
 func (s *Usage) PrintError(err error) {
 	s.formatter.PrintError(err)
-	os.Exit(1)
+	os.Exit(ExitCode(err))
 }
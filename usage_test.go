@@ -1,8 +1,14 @@
 package usage_test
 
 import (
+	"bytes"
+	"flag"
 	"github.com/bgrewell/usage"
+	"github.com/bgrewell/usage/internal"
+	"github.com/bgrewell/usage/pkg"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -36,6 +42,19 @@ func TestWithApplicationBranch(t *testing.T) {
 	assert.Equal(t, "main", sage.ApplicationBranch())
 }
 
+func TestWithBuildInfoKeepsExplicitValues(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithBuildInfo(),
+		usage.WithApplicationVersion("1.0.0"),
+		usage.WithApplicationCommitHash("abc123"),
+		usage.WithApplicationBuildDate("2024-01-15"),
+	)
+	assert.Equal(t, "1.0.0", sage.ApplicationVersion())
+	assert.Equal(t, "abc123", sage.ApplicationCommitHash())
+	assert.Equal(t, "2024-01-15", sage.ApplicationBuildDate())
+	assert.False(t, sage.ApplicationModified())
+}
+
 func TestWithApplicationDescription(t *testing.T) {
 	sage := usage.NewUsage(usage.WithApplicationDescription("Test Description"))
 	assert.Equal(t, "Test Description", sage.ApplicationDescription())
@@ -67,3 +86,312 @@ func TestNewUseSageWithNoOptions(t *testing.T) {
 	assert.Empty(t, sage.ApplicationBranch())
 	assert.Empty(t, sage.ApplicationDescription())
 }
+
+func TestParseArgs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	sage := usage.NewUsage(usage.WithApplicationName("TestApp"), usage.WithFlagSet(fs))
+	timeout, err := sage.AddIntegerOptionE("t", "timeout", 10, "Timeout in seconds", "", nil)
+	assert.NoError(t, err)
+	url := sage.AddArgument(1, "url", "The url", "")
+
+	err = sage.ParseArgs([]string{"--timeout", "5", "http://example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, *timeout)
+	assert.Equal(t, "http://example.com", *url)
+	assert.Equal(t, flag.ContinueOnError, fs.ErrorHandling())
+}
+
+func TestParseArgsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantKind   internal.ErrorKind
+		wantOption string
+	}{
+		{"unknown option", []string{"--timout", "5"}, internal.ErrorKindUnknownOption, "timout"},
+		{"invalid value", []string{"--timeout", "abc"}, internal.ErrorKindInvalidValue, "timeout"},
+		{"missing value", []string{"--timeout"}, internal.ErrorKindMissingValue, "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ExitOnError)
+			sage := usage.NewUsage(usage.WithFlagSet(fs))
+			_, err := sage.AddIntegerOptionE("t", "timeout", 10, "Timeout in seconds", "", nil)
+			assert.NoError(t, err)
+
+			err = sage.ParseArgs(tt.args)
+			var usageErr *usage.UsageError
+			if assert.ErrorAs(t, err, &usageErr) {
+				assert.Equal(t, tt.wantKind, usageErr.Kind)
+				assert.Equal(t, tt.wantOption, usageErr.Option)
+			}
+			assert.Equal(t, flag.ExitOnError, fs.ErrorHandling())
+		})
+	}
+}
+
+func TestParseArgsHelp(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	sage := usage.NewUsage(usage.WithFlagSet(fs))
+	err := sage.ParseArgs([]string{"--help"})
+	assert.ErrorIs(t, err, usage.ErrHelp)
+}
+
+func TestDeprecateOption(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	sage := usage.NewUsage(
+		usage.WithFlagSet(fs),
+		usage.WithFormatter(pkg.NewStandardFormatter(&outBuf, &errBuf, nil)),
+	)
+	_, err := sage.AddStringOptionE("", "out", "", "Output file", "", nil)
+	assert.NoError(t, err)
+	output, err := sage.AddStringOptionE("o", "output", "", "Output file", "", nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, sage.DeprecateOption("out", "renamed", "output"))
+	assert.NoError(t, sage.ParseArgs([]string{"--output", "a.txt"}))
+	assert.Empty(t, errBuf.String())
+	assert.Equal(t, "a.txt", *output)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--out", "b.txt"}))
+	assert.Contains(t, errBuf.String(), "option --out is deprecated: renamed, use --output instead")
+}
+
+func TestOptionVisibilityNotFound(t *testing.T) {
+	sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+	assert.ErrorIs(t, sage.HideOption("missing"), usage.ErrOptionNotFound)
+	assert.ErrorIs(t, sage.SetOptionAdvanced("missing"), usage.ErrOptionNotFound)
+	assert.ErrorIs(t, sage.DeprecateOption("missing", "", ""), usage.ErrOptionNotFound)
+}
+
+func TestParseArgsHelpAll(t *testing.T) {
+	sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+	assert.ErrorIs(t, sage.ParseArgs([]string{"--help-all"}), usage.ErrHelp)
+}
+
+func TestParseArgsSuggestions(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []usage.UsageOption
+		args        []string
+		suggestions []string
+		message     string
+	}{
+		{
+			name:        "default distance",
+			args:        []string{"--timout", "5"},
+			suggestions: []string{"--timeout"},
+			message:     "did you mean --timeout?",
+		},
+		{
+			name:        "custom distance",
+			options:     []usage.UsageOption{usage.WithSuggestionDistance(1)},
+			args:        []string{"--tmout", "5"},
+			suggestions: nil,
+		},
+		{
+			name:        "disabled",
+			options:     []usage.UsageOption{usage.WithoutSuggestions()},
+			args:        []string{"--timout", "5"},
+			suggestions: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]usage.UsageOption{usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError))}, tt.options...)
+			sage := usage.NewUsage(options...)
+			_, err := sage.AddIntegerOptionE("t", "timeout", 10, "Timeout in seconds", "", nil)
+			assert.NoError(t, err)
+
+			err = sage.ParseArgs(tt.args)
+			var usageErr *usage.UsageError
+			if assert.ErrorAs(t, err, &usageErr) {
+				assert.Equal(t, tt.suggestions, usageErr.Suggestions)
+				assert.Contains(t, usageErr.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestParseArgsAbbreviations(t *testing.T) {
+	tests := []struct {
+		name          string
+		abbreviations bool
+		negation      bool
+		args          []string
+		wantVerbose   bool
+		wantTimeout   int
+		wantKind      internal.ErrorKind
+	}{
+		{"exact", false, false, []string{"--verbose", "--timeout", "5"}, true, 5, ""},
+		{"disabled", false, false, []string{"--verb"}, false, 10, internal.ErrorKindUnknownOption},
+		{"unique prefix", true, false, []string{"--verb", "--time", "5"}, true, 5, ""},
+		{"negated", false, true, []string{"--verbose", "--no-verbose"}, false, 10, ""},
+		{"negated prefix", true, true, []string{"--verbose", "--no-verb"}, false, 10, ""},
+		{"negation disabled", true, false, []string{"--no-verbose"}, false, 10, internal.ErrorKindUnknownOption},
+		{"ambiguous", true, false, []string{"--ver"}, false, 10, internal.ErrorKindAmbiguousOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := []usage.UsageOption{usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError))}
+			if tt.negation {
+				options = append(options, usage.WithNegatedBooleans())
+			}
+			if tt.abbreviations {
+				options = append(options, usage.WithAbbreviations())
+			}
+			sage := usage.NewUsage(options...)
+			verbose, err := sage.AddBooleanOptionE("", "verbose", false, "Verbose output", "", nil)
+			assert.NoError(t, err)
+			_, err = sage.AddBooleanOptionE("", "version", false, "Show the version", "", nil)
+			assert.NoError(t, err)
+			timeout, err := sage.AddIntegerOptionE("", "timeout", 10, "Timeout in seconds", "", nil)
+			assert.NoError(t, err)
+
+			err = sage.ParseArgs(tt.args)
+			if tt.wantKind != "" {
+				var usageErr *usage.UsageError
+				if assert.ErrorAs(t, err, &usageErr) {
+					assert.Equal(t, tt.wantKind, usageErr.Kind)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantVerbose, *verbose)
+			assert.Equal(t, tt.wantTimeout, *timeout)
+		})
+	}
+}
+
+func TestParseArgsResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "args.rsp")
+	assert.NoError(t, os.WriteFile(path, []byte("# request settings\n--timeout 5\n--name 'John Doe'\n--timout 3\n"), 0o644))
+
+	newUsage := func(options ...usage.UsageOption) (*usage.Usage, *int, *string) {
+		options = append(options, usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+		sage := usage.NewUsage(options...)
+		timeout, err := sage.AddIntegerOptionE("t", "timeout", 10, "Timeout in seconds", "", nil)
+		assert.NoError(t, err)
+		name, err := sage.AddStringOptionE("n", "name", "", "Name", "", nil)
+		assert.NoError(t, err)
+		return sage, timeout, name
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		sage, _, _ := newUsage()
+		file := sage.AddArgument(1, "file", "File", "")
+		assert.NoError(t, sage.ParseArgs([]string{"@" + path}))
+		assert.Equal(t, "@"+path, *file)
+	})
+
+	t.Run("error references file and line", func(t *testing.T) {
+		sage, timeout, name := newUsage(usage.WithResponseFiles())
+		err := sage.ParseArgs([]string{"@" + path})
+		var usageErr *usage.UsageError
+		if assert.ErrorAs(t, err, &usageErr) {
+			assert.Equal(t, path+":4", usageErr.Source)
+			assert.Contains(t, usageErr.Error(), path+":4: flag provided but not defined: -timout")
+		}
+		assert.Equal(t, 5, *timeout)
+		assert.Equal(t, "John Doe", *name)
+	})
+}
+
+func TestVersionFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []usage.UsageOption
+		args     []string
+		wantErr  error
+		wantKind internal.ErrorKind
+	}{
+		{"default long name", nil, []string{"--version"}, usage.ErrVersion, ""},
+		{"detailed", nil, []string{"--version=detailed"}, usage.ErrVersion, ""},
+		{"invalid format", nil, []string{"--version=xml"}, nil, internal.ErrorKindInvalidValue},
+		{"not requested", nil, []string{}, nil, ""},
+		{"custom names", []usage.UsageOption{usage.WithVersionFlag("V", "build-info")}, []string{"-V"}, usage.ErrVersion, ""},
+		{"disabled", []usage.UsageOption{usage.WithoutVersionFlag()}, []string{"--version"}, nil, internal.ErrorKindUnknownOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]usage.UsageOption{usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError))}, tt.options...)
+			sage := usage.NewUsage(options...)
+
+			err := sage.ParseArgs(tt.args)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantKind != "":
+				var usageErr *usage.UsageError
+				if assert.ErrorAs(t, err, &usageErr) {
+					assert.Equal(t, tt.wantKind, usageErr.Kind)
+				}
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVersionFlagExistingOption(t *testing.T) {
+	sage := usage.NewUsage(usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)))
+	version, err := sage.AddStringOptionE("", "version", "", "API version", "", nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, sage.ParseArgs([]string{"--version", "v2"}))
+	assert.Equal(t, "v2", *version)
+}
+
+func TestFormatVersion(t *testing.T) {
+	sage := usage.NewUsage(
+		usage.WithApplicationName("myapp"),
+		usage.WithApplicationVersion("1.2.3"),
+		usage.WithApplicationCommitHash("abc123"),
+		usage.WithVersionTemplate("{{.Name}} v{{.Version}} ({{.CommitHash}})"),
+	)
+
+	version, err := sage.FormatVersion(usage.VERSION_SHORT)
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp v1.2.3 (abc123)\n", version)
+
+	version, err = sage.FormatVersion(usage.VERSION_DETAILED)
+	assert.NoError(t, err)
+	assert.Contains(t, version, "Commit:     abc123")
+	assert.Contains(t, version, "Go version: go")
+
+	version, err = sage.FormatVersion(usage.VERSION_JSON)
+	assert.NoError(t, err)
+	assert.Contains(t, version, `"version": "1.2.3"`)
+}
+
+// errorOnlyFormatter implements only the methods of the Formatter interface,
+// without PrintWarning.
+type errorOnlyFormatter struct{}
+
+func (errorOnlyFormatter) PrintUsage()          {}
+func (errorOnlyFormatter) PrintError(err error) {}
+
+func TestWithFormatterWithoutWarnings(t *testing.T) {
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	assert.NoError(t, err)
+	defer func(original *os.File) { os.Stderr = original }(os.Stderr)
+	os.Stderr = stderr
+
+	sage := usage.NewUsage(
+		usage.WithFlagSet(flag.NewFlagSet("test", flag.ContinueOnError)),
+		usage.WithFormatter(errorOnlyFormatter{}),
+	)
+	sage.AddStringOption("", "out", "", "Output file", "", nil)
+	assert.NoError(t, sage.DeprecateOption("out", "renamed", ""))
+	assert.NoError(t, sage.ParseArgs([]string{"--out", "a.txt"}))
+
+	data, err := os.ReadFile(stderr.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "Warning: option --out is deprecated: renamed\n", string(data))
+}